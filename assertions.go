@@ -2,29 +2,72 @@ package assert
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/go-openapi/spec"
 	"github.com/xeipuuv/gojsonschema"
 )
 
 // Assertions packs all assert methods into one structure.
 type Assertions struct {
-	doc Document
+	doc              Document
+	strict           bool
+	allowedHeaders   map[string]bool
+	maxBodySize      int64
+	streaming        bool
+	decoders         map[string]ContextDecoder
+	pathPrefix       string
+	validateServer   bool
+	validateAccept   bool
+	validateSecurity bool
+	reporter         func(error)
+	severities       map[Check]Severity
 }
 
 // New returns the Assertions instance.
-func New(doc Document) *Assertions {
-	return &Assertions{doc}
+func New(doc Document, opts ...Option) *Assertions {
+	a := &Assertions{
+		doc:            doc,
+		allowedHeaders: map[string]bool{},
+		decoders:       map[string]ContextDecoder{},
+		severities:     map[Check]Severity{},
+	}
+
+	for mediaType, decoder := range defaultDecoders {
+		a.decoders[mediaType] = decoder.withContext()
+	}
+
+	WithAllowedHeaders(defaultAllowedHeaders...)(a)
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
 }
 
 // RequestMediaType asserts request media type against a list.
 func (a *Assertions) RequestMediaType(mediaType, path, method string) error {
+	return a.RequestMediaTypeContext(context.Background(), mediaType, path, method)
+}
+
+// RequestMediaTypeContext is like RequestMediaType, stopping when the context is done.
+func (a *Assertions) RequestMediaTypeContext(ctx context.Context, mediaType, path, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	types, err := a.doc.RequestMediaTypes(path, method)
 	if err != nil {
 		return err
@@ -38,11 +81,20 @@ func (a *Assertions) RequestMediaType(mediaType, path, method string) error {
 
 	ts := strings.Join(types, ", ")
 
-	return failf(`'%s' is an allowed media type (%s)`, mediaType, ts)
+	return a.finding(ctx, CheckRequestMediaType, mediaTypeFailure(mediaType, ts, "consumes"))
 }
 
 // ResponseMediaType asserts response media type against a list.
 func (a *Assertions) ResponseMediaType(mediaType, path, method string) error {
+	return a.ResponseMediaTypeContext(context.Background(), mediaType, path, method)
+}
+
+// ResponseMediaTypeContext is like ResponseMediaType, stopping when the context is done.
+func (a *Assertions) ResponseMediaTypeContext(ctx context.Context, mediaType, path, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	types, err := a.doc.ResponseMediaTypes(path, method)
 	if err != nil {
 		return err
@@ -56,87 +108,123 @@ func (a *Assertions) ResponseMediaType(mediaType, path, method string) error {
 
 	ts := strings.Join(types, ", ")
 
-	return failf(`'%s' is an allowed media type (%s)`, mediaType, ts)
+	return a.finding(ctx, CheckResponseMediaType, mediaTypeFailure(mediaType, ts, "produces"))
 }
 
-// RequestHeaders asserts rquest headers againt a schema header list.
-func (a *Assertions) RequestHeaders(header http.Header, path, method string) error {
-	schema, err := a.doc.RequestHeaders(path, method)
+// RequestAccept asserts the request accept header against the media types
+// produced by the operation.
+func (a *Assertions) RequestAccept(accept, path, method string) error {
+	return a.RequestAcceptContext(context.Background(), accept, path, method)
+}
+
+// RequestAcceptContext is like RequestAccept, stopping when the context is done.
+func (a *Assertions) RequestAcceptContext(ctx context.Context, accept, path, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	types, err := a.doc.ResponseMediaTypes(path, method)
 	if err != nil {
 		return err
 	}
 
-	headers := map[string]string{}
+	if accept == "" || len(types) == 0 || acceptable(accept, types) {
+		return nil
+	}
+
+	ts := strings.Join(types, ", ")
 
-	for k, v := range header {
-		headers[k] = strings.Join(v, ", ")
+	issue := Issue{
+		Location: "header",
+		Pointer:  "/accept",
+		Keyword:  "produces",
+		Message:  fmt.Sprintf("%s does not accept any of %s", accept, ts),
 	}
 
-	result, err := a.validate(schema, headers)
-	if err != nil {
+	return a.finding(ctx, CheckRequestAccept, failIssues([]Issue{issue}, `'%s' accepts an allowed media type (%s)`, accept, ts))
+}
+
+// RequestHeaders asserts rquest headers againt a schema header list.
+func (a *Assertions) RequestHeaders(header http.Header, path, method string) error {
+	return a.RequestHeadersContext(context.Background(), header, path, method)
+}
+
+// RequestHeadersContext is like RequestHeaders, stopping when the context is done.
+func (a *Assertions) RequestHeadersContext(ctx context.Context, header http.Header, path, method string) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	if result.Valid() {
-		return nil
+	schema, err := a.doc.RequestHeaders(path, method)
+	if err != nil {
+		return err
 	}
 
-	data, err := json.Marshal(headers)
+	headers := headerValues(header, schema)
+
+	result, err := a.validate(schema, headers)
 	if err != nil {
 		return err
 	}
 
-	errorMessages := []string{}
+	undeclared := []Issue{}
 
-	for _, v := range result.Errors() {
-		errorMessages = append(errorMessages, v.Description())
+	if a.strict {
+		undeclared = undeclaredHeaders(header, schema, a.allowedHeader)
 	}
 
-	errs := strings.Join(errorMessages, ", ")
-
-	return failf(`'%s' is a valid request header (%s)`, string(data), errs)
+	return firstError(
+		a.finding(ctx, CheckRequestHeaders, violation("request header", headers, resultIssues("header", "", result))),
+		a.finding(ctx, CheckUndeclaredRequestHeader, violation("request header", headers, undeclared)),
+	)
 }
 
 // ResponseHeaders asserts response headers againt a schema header list.
 func (a *Assertions) ResponseHeaders(header http.Header, path, method string, statusCode int) error {
-	schema, err := a.doc.ResponseHeaders(path, method, statusCode)
-	if err != nil {
-		return err
-	}
-
-	headers := map[string]string{}
+	return a.ResponseHeadersContext(context.Background(), header, path, method, statusCode)
+}
 
-	for k, v := range header {
-		headers[k] = strings.Join(v, ", ")
+// ResponseHeadersContext is like ResponseHeaders, stopping when the context is done.
+func (a *Assertions) ResponseHeadersContext(ctx context.Context, header http.Header, path, method string, statusCode int) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	result, err := a.validate(schema, headers)
+	schema, err := a.doc.ResponseHeaders(path, method, statusCode)
 	if err != nil {
 		return err
 	}
 
-	if result.Valid() {
-		return nil
-	}
+	headers := headerValues(header, schema)
 
-	data, err := json.Marshal(headers)
+	result, err := a.validate(schema, headers)
 	if err != nil {
 		return err
 	}
 
-	errorMessages := []string{}
+	undeclared := []Issue{}
 
-	for _, v := range result.Errors() {
-		errorMessages = append(errorMessages, v.Description())
+	if a.strict {
+		undeclared = undeclaredHeaders(header, schema, a.allowedHeader)
 	}
 
-	errs := strings.Join(errorMessages, ", ")
-
-	return failf(`'%s' is a valid response header (%s)`, string(data), errs)
+	return firstError(
+		a.finding(ctx, CheckResponseHeaders, violation("response header", headers, resultIssues("header", "", result))),
+		a.finding(ctx, CheckUndeclaredResponseHeader, violation("response header", headers, undeclared)),
+	)
 }
 
 // RequestQuery asserts request query againt a schema query list.
 func (a *Assertions) RequestQuery(query url.Values, path, method string) error {
+	return a.RequestQueryContext(context.Background(), query, path, method)
+}
+
+// RequestQueryContext is like RequestQuery, stopping when the context is done.
+func (a *Assertions) RequestQueryContext(ctx context.Context, query url.Values, path, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	schema, err := a.doc.RequestQuery(path, method)
 	if err != nil {
 		return err
@@ -147,151 +235,482 @@ func (a *Assertions) RequestQuery(query url.Values, path, method string) error {
 		return err
 	}
 
-	if result.Valid() {
-		return nil
+	undeclared := []Issue{}
+
+	if a.strict {
+		names := []string{}
+		for k := range query {
+			names = append(names, k)
+		}
+
+		for _, name := range undeclaredNames(names, schema, nil) {
+			undeclared = append(undeclared, undeclaredIssue("query", name))
+		}
 	}
 
-	data, err := json.Marshal(query)
-	if err != nil {
+	return firstError(
+		a.finding(ctx, CheckRequestQuery, violation("request query", query, resultIssues("query", "", result))),
+		a.finding(ctx, CheckUndeclaredQuery, violation("request query", query, undeclared)),
+	)
+}
+
+// RequestBody asserts request body against a schema.
+func (a *Assertions) RequestBody(body io.Reader, path, method string) error {
+	return a.RequestBodyContext(context.Background(), body, path, method)
+}
+
+// RequestBodyContext is like RequestBody, stopping when the context is done.
+func (a *Assertions) RequestBodyContext(ctx context.Context, body io.Reader, path, method string) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
 
-	errorMessages := []string{}
+	return a.assertRequestBody(ctx, body, "", path, method)
+}
+
+// RequestBodyWithMediaType asserts request body against a schema, decoding
+// the body according to its media type.
+func (a *Assertions) RequestBodyWithMediaType(body io.Reader, mediaType, path, method string) error {
+	return a.RequestBodyWithMediaTypeContext(context.Background(), body, mediaType, path, method)
+}
 
-	for _, v := range result.Errors() {
-		errorMessages = append(errorMessages, v.Description())
+// RequestBodyWithMediaTypeContext is like RequestBodyWithMediaType, stopping when the context is done.
+func (a *Assertions) RequestBodyWithMediaTypeContext(ctx context.Context, body io.Reader, mediaType, path, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	errs := strings.Join(errorMessages, ", ")
+	return a.assertRequestBody(ctx, body, mediaType, path, method)
+}
 
-	return failf(`'%s' is a valid request query (%s)`, string(data), errs)
+// ResponseBody asserts response body against a schema.
+func (a *Assertions) ResponseBody(body io.Reader, path, method string, statusCode int) error {
+	return a.ResponseBodyContext(context.Background(), body, path, method, statusCode)
 }
 
-// RequestBody asserts request body against a schema.
-func (a *Assertions) RequestBody(body io.Reader, path, method string) error {
-	schema, err := a.doc.RequestBody(path, method)
+// ResponseBodyContext is like ResponseBody, stopping when the context is done.
+func (a *Assertions) ResponseBodyContext(ctx context.Context, body io.Reader, path, method string, statusCode int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	schema, err := a.doc.ResponseBody(path, method, statusCode)
 	if err != nil {
 		return err
 	}
 
-	data, err := ioutil.ReadAll(body)
+	return a.assertBody(ctx, schema, body, "", "response body")
+}
+
+// ResponseBodyWithMediaType asserts response body against a schema, decoding
+// the body according to its media type.
+func (a *Assertions) ResponseBodyWithMediaType(body io.Reader, mediaType, path, method string, statusCode int) error {
+	return a.ResponseBodyWithMediaTypeContext(context.Background(), body, mediaType, path, method, statusCode)
+}
+
+// ResponseBodyWithMediaTypeContext is like ResponseBodyWithMediaType, stopping when the context is done.
+func (a *Assertions) ResponseBodyWithMediaTypeContext(ctx context.Context, body io.Reader, mediaType, path, method string, statusCode int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	schema, err := a.doc.ResponseBody(path, method, statusCode)
 	if err != nil {
 		return err
 	}
 
-	result, err := a.validate(schema, data)
+	return a.assertBody(ctx, schema, body, mediaType, "response body")
+}
+
+// Request asserts http request against a schema.
+func (a *Assertions) Request(req *http.Request) error {
+	return a.RequestContext(context.Background(), req)
+}
+
+// RequestContext is like Request, stopping when the context is done.
+func (a *Assertions) RequestContext(ctx context.Context, req *http.Request) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r, err := a.resolve(req)
 	if err != nil {
 		return err
 	}
 
-	if result.Valid() {
-		return nil
+	return r.assertRequest(ctx, req)
+}
+
+// assertRequest asserts http request against the resolved document.
+func (a *Assertions) assertRequest(ctx context.Context, req *http.Request) error {
+	path := a.lookupPath(req.URL)
+	method := req.Method
+
+	if err := a.finding(ctx, CheckServer, a.assertServer(req)); err != nil {
+		return err
 	}
 
-	errorMessages := []string{}
+	// The operation is looked up once, for the checks needing it.
+	op, _ := documentOperation(a.doc, path, method)
 
-	for _, v := range result.Errors() {
-		errorMessages = append(errorMessages, v.Description())
+	if a.validateSecurity {
+		if err := a.finding(ctx, CheckSecurity, a.assertSecurity(req, op)); err != nil {
+			return err
+		}
 	}
 
-	errs := strings.Join(errorMessages, ", ")
+	if err := a.RequestHeadersContext(ctx, req.Header, path, method); err != nil {
+		return err
+	}
 
-	return failf(`'%s' is a valid request body (%s)`, string(data), errs)
-}
+	if a.validateAccept {
+		if err := a.RequestAcceptContext(ctx, req.Header.Get("accept"), path, method); err != nil {
+			return err
+		}
+	}
 
-// ResponseBody asserts response body against a schema.
-func (a *Assertions) ResponseBody(body io.Reader, path, method string, statusCode int) error {
-	schema, err := a.doc.ResponseBody(path, method, statusCode)
+	data, err := a.bufferBody(ctx, &req.Body)
 	if err != nil {
 		return err
 	}
 
-	data, err := ioutil.ReadAll(body)
+	// Requests without body, like the ones received by a server with a
+	// http.NoBody, need no media type.
+	hasBody := len(bytes.TrimSpace(data)) > 0
+
+	if err := a.RequestMediaTypeContext(ctx, req.Header.Get("content-type"), path, method); err != nil && hasBody {
+		return err
+	}
+
+	if err := a.RequestQueryContext(ctx, req.URL.Query(), path, method); err != nil {
+		return err
+	}
+
+	if err := a.reportDeprecations(ctx, req, op, data); err != nil {
+		return err
+	}
+
+	schema, err := a.doc.RequestBody(path, method)
+	if err != nil && err == ErrBodyNotFound {
+		return nil
+	}
+
 	if err != nil {
 		return err
 	}
 
-	result, err := a.validate(schema, data)
+	required, err := requestBodyRequired(a.doc, path, method)
 	if err != nil {
 		return err
 	}
 
-	if result.Valid() {
-		return nil
+	if !hasBody {
+		return a.missingBody(ctx, required)
 	}
 
-	errorMessages := []string{}
+	return a.assertBodyBytes(ctx, schema, data, req.Header.Get("content-type"), "request body")
+}
+
+// Response asserts http response against a schema. Responses whose status
+// code declares no schema are accepted without checking their body.
+func (a *Assertions) Response(res *http.Response) error {
+	return a.ResponseContext(context.Background(), res)
+}
 
-	for _, v := range result.Errors() {
-		errorMessages = append(errorMessages, v.Description())
+// ResponseContext is like Response, stopping when the context is done.
+func (a *Assertions) ResponseContext(ctx context.Context, res *http.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	errs := strings.Join(errorMessages, ", ")
+	r, err := a.resolve(res.Request)
+	if err != nil {
+		return err
+	}
 
-	return failf(`'%s' is a valid response body (%s)`, string(data), errs)
+	return r.assertResponse(ctx, res)
 }
 
-// Request asserts http request against a schema.
-func (a *Assertions) Request(req *http.Request) error {
-	path := req.URL.String()
-	method := req.Method
+// assertResponse asserts http response against the resolved document.
+func (a *Assertions) assertResponse(ctx context.Context, res *http.Response) error {
+	path := a.lookupPath(res.Request.URL)
+	method := res.Request.Method
+	statusCode := res.StatusCode
 
-	if err := a.RequestHeaders(req.Header, path, method); err != nil {
+	if err := a.finding(ctx, CheckServer, a.assertServer(res.Request)); err != nil {
 		return err
 	}
 
-	if err := a.RequestMediaType(req.Header.Get("content-type"), path, method); err != nil && req.Body != nil {
+	if err := a.ResponseHeadersContext(ctx, res.Header, path, method, statusCode); err != nil {
 		return err
 	}
 
-	if err := a.RequestQuery(req.URL.Query(), path, method); err != nil {
+	hasBody := res.Body != nil && res.Body != http.NoBody
+
+	if err := a.ResponseMediaTypeContext(ctx, res.Header.Get("content-type"), path, method); err != nil && hasBody {
 		return err
 	}
 
-	buf := bytes.NewBuffer(make([]byte, 0))
-	reader := io.TeeReader(req.Body, buf)
-	req.Body = ioutil.NopCloser(buf)
+	data, err := a.bufferBody(ctx, &res.Body)
+	if err != nil {
+		return err
+	}
 
-	err := a.RequestBody(reader, path, method)
-	if err != nil && err == ErrBodyNotFound {
+	schema, err := a.doc.ResponseBody(path, method, statusCode)
+	if errors.Is(err, ErrBodyNotFound) {
 		return nil
 	}
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	return a.assertBodyBytes(ctx, schema, data, res.Header.Get("content-type"), "response body")
 }
 
-// Response asserts http response against a schema.
-func (a *Assertions) Response(res *http.Response) error {
-	path := res.Request.URL.Path
-	method := res.Request.Method
-	statusCode := res.StatusCode
+// resolve returns the assertions of the document resolved for the request,
+// when the document is a Resolver. The resolver receives the lookup path.
+func (a *Assertions) resolve(req *http.Request) (*Assertions, error) {
+	resolver, ok := a.doc.(Resolver)
+	if !ok {
+		return a, nil
+	}
 
-	if err := a.ResponseHeaders(res.Header, path, method, statusCode); err != nil {
-		return err
+	u := *req.URL
+	u.Path = a.lookupPath(req.URL)
+
+	r := *req
+	r.URL = &u
+
+	doc, err := resolver.Resolve(&r)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := a.ResponseMediaType(res.Header.Get("content-type"), path, method); err != nil && res.Body != nil {
-		return err
+	return a.withDocument(doc), nil
+}
+
+// bufferBody reads the body once and replaces it with the buffered data, so
+// it can be read again by the next handler. When the body exceeds the maximum
+// size, the unread data is kept after the buffered one.
+func (a *Assertions) bufferBody(ctx context.Context, body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return []byte{}, nil
+	}
+
+	data, err := a.readBody(ctx, *body)
+	if err != nil {
+		*body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(data), *body))
+		return nil, err
 	}
 
-	buf := bytes.NewBuffer(make([]byte, 0))
-	reader := io.TeeReader(res.Body, buf)
-	res.Body = ioutil.NopCloser(buf)
+	*body = ioutil.NopCloser(bytes.NewReader(data))
 
-	return a.ResponseBody(reader, path, method, statusCode)
+	return data, nil
 }
 
 func (a *Assertions) validate(schema, data interface{}) (*gojsonschema.Result, error) {
-	loader := gojsonschema.NewGoLoader(data)
+	return gojsonschema.Validate(
+		gojsonschema.NewGoLoader(schema),
+		dataLoader(data),
+	)
+}
 
+// dataLoader returns the loader of the validated data, raw json when the
+// data is a []byte.
+func dataLoader(data interface{}) gojsonschema.JSONLoader {
 	if b, ok := data.([]byte); ok {
-		loader = gojsonschema.NewBytesLoader(b)
+		return gojsonschema.NewBytesLoader(b)
 	}
 
-	return gojsonschema.Validate(
-		gojsonschema.NewGoLoader(schema),
-		loader,
-	)
+	return gojsonschema.NewGoLoader(data)
+}
+
+// acceptable reports whether one of the media types matches the accept
+// header. Malformed accept headers are accepted.
+func acceptable(accept string, types []string) bool {
+	parsed := false
+
+	for _, r := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil {
+			continue
+		}
+
+		parsed = true
+
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+
+		for _, t := range types {
+			if tm, _, err := mime.ParseMediaType(t); err == nil {
+				t = tm
+			}
+
+			if mt == "*/*" || mt == t || (strings.HasSuffix(mt, "/*") && strings.HasPrefix(t, mt[:len(mt)-1])) {
+				return true
+			}
+		}
+	}
+
+	return !parsed
+}
+
+// violation returns the failure for the issues of a header or query check,
+// or nil when there are none.
+func violation(kind string, values interface{}, issues []Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	errs := strings.Join(issueMessages(issues), ", ")
+
+	return failIssues(issues, `'%s' is a valid %s (%s)`, string(data), kind, errs)
+}
+
+// mediaTypeFailure returns the failure for a media type not listed by the
+// keyword.
+func mediaTypeFailure(mediaType, types, keyword string) error {
+	issue := Issue{
+		Location: "header",
+		Pointer:  "/content-type",
+		Keyword:  keyword,
+		Message:  fmt.Sprintf("%s is not one of %s", mediaType, types),
+	}
+
+	return failIssues([]Issue{issue}, `'%s' is an allowed media type (%s)`, mediaType, types)
+}
+
+// undeclaredIssue returns the issue of an undeclared name.
+func undeclaredIssue(location, name string) Issue {
+	return Issue{
+		Location: location,
+		Pointer:  "/" + escapePointer(name),
+		Keyword:  "undeclared",
+		Message:  fmt.Sprintf("%s is not declared", name),
+	}
+}
+
+// firstError returns the first non nil error.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// undeclaredHeaders returns an issue for every header not declared in the
+// schema nor allowed.
+func undeclaredHeaders(header http.Header, schema Headers, allowed func(string) bool) []Issue {
+	names := []string{}
+	for k := range header {
+		names = append(names, k)
+	}
+
+	issues := []Issue{}
+
+	for _, name := range undeclaredNames(names, schema, allowed) {
+		issues = append(issues, undeclaredIssue("header", strings.ToLower(name)))
+	}
+
+	return issues
+}
+
+// allowedHeader reports whether the header is accepted without being declared.
+func (a *Assertions) allowedHeader(name string) bool {
+	return a.allowedHeaders[http.CanonicalHeaderKey(name)]
+}
+
+// undeclaredBody returns an issue for every body property not declared in
+// the schema, with pointers relative to the prefix. Raw json data is decoded
+// before the check.
+func undeclaredBody(schema Body, value interface{}, prefix string) []Issue {
+	s, ok := schema.(*spec.Schema)
+	if !ok {
+		return nil
+	}
+
+	body := value
+
+	if data, ok := value.([]byte); ok {
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil
+		}
+	}
+
+	issues := []Issue{}
+
+	for _, pointer := range undeclaredProperties(s, body, "") {
+		issues = append(issues, Issue{
+			Location: "body",
+			Pointer:  prefix + pointer,
+			Keyword:  "undeclared",
+			Message:  fmt.Sprintf("%s is not declared", pointer),
+		})
+	}
+
+	return issues
+}
+
+// headerValues normalizes the header names to lower case, merging the values
+// of names that differ only by case, and keeps repeated values as an array
+// when the schema declares the header as an array.
+func headerValues(header http.Header, schema Headers) map[string]interface{} {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	merged := map[string][]string{}
+
+	for _, k := range keys {
+		name := strings.ToLower(k)
+		merged[name] = append(merged[name], header[k]...)
+	}
+
+	headers := map[string]interface{}{}
+
+	for name, v := range merged {
+		if schemaType(schema[name]) != "array" {
+			headers[name] = strings.Join(v, ", ")
+			continue
+		}
+
+		items := []string{}
+
+		for _, value := range v {
+			for _, item := range strings.Split(value, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+
+		headers[name] = items
+	}
+
+	return headers
+}
+
+// schemaType returns the type of a header or query schema entry.
+func schemaType(v interface{}) string {
+	switch p := v.(type) {
+	case *Param:
+		return p.Type
+	case spec.Header:
+		return p.Type
+	}
+
+	return ""
 }
 
 func failf(format string, a ...interface{}) error {
@@ -96,6 +96,7 @@ func TestAssertionsRequestHeaders(t *testing.T) {
 		path    string
 		method  string
 		headers map[string][]string
+		opts    []Option
 		err     string
 	}
 
@@ -113,15 +114,104 @@ func TestAssertionsRequestHeaders(t *testing.T) {
 		err:    "failed asserting that '{}' is a valid request header (x-required-header is required)",
 	})
 
+	tests.Add("canonical headers", tt{
+		path:   "/api/pets/1",
+		method: http.MethodPatch,
+		headers: http.Header{
+			"X-Required-Header": {"value"},
+		},
+	})
+
+	tests.Add("raw headers", tt{
+		path:   "/api/pets/1",
+		method: http.MethodPatch,
+		headers: map[string][]string{
+			"X-REQUIRED-HEADER": {"value"},
+		},
+	})
+
+	tests.Add("strict undeclared", tt{
+		path:   "/api/pets/1",
+		method: http.MethodPatch,
+		headers: http.Header{
+			"X-Required-Header": {"value"},
+			"X-Unknown":         {"value"},
+			"User-Agent":        {"test"},
+		},
+		opts: []Option{WithStrict()},
+		err:  `failed asserting that '{"user-agent":"test","x-required-header":"value","x-unknown":"value"}' is a valid request header (x-unknown is not declared)`,
+	})
+
+	tests.Add("strict allowed", tt{
+		path:   "/api/pets/1",
+		method: http.MethodPatch,
+		headers: http.Header{
+			"X-Required-Header": {"value"},
+			"X-Unknown":         {"value"},
+		},
+		opts: []Option{WithStrict(), WithAllowedHeaders("x-unknown")},
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		doc, _ := LoadFromURI("./fixtures/docs.json")
-		assertions := New(doc)
+		assertions := New(doc, tt.opts...)
 
 		err := assertions.RequestHeaders(tt.headers, tt.path, tt.method)
 		testy.Error(t, tt.err, err)
 	})
 }
 
+func TestHeaderValues(t *testing.T) {
+	type tt struct {
+		header   http.Header
+		schema   Headers
+		expected map[string]interface{}
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("canonical headers", tt{
+		header: http.Header{
+			"X-Request-Id": {"1", "2"},
+		},
+		schema: Headers{},
+		expected: map[string]interface{}{
+			"x-request-id": "1, 2",
+		},
+	})
+
+	tests.Add("raw headers", tt{
+		header: http.Header{
+			"X-REQUEST-ID": {"1"},
+			"x-request-id": {"2"},
+		},
+		schema: Headers{},
+		expected: map[string]interface{}{
+			"x-request-id": "1, 2",
+		},
+	})
+
+	tests.Add("array header", tt{
+		header: http.Header{
+			"X-Tags": {"a, b", "c"},
+		},
+		schema: Headers{
+			"x-tags": &Param{Type: "array", In: "header"},
+		},
+		expected: map[string]interface{}{
+			"x-tags": []string{"a", "b", "c"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got := headerValues(tt.header, tt.schema)
+
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
 func TestAssertionsResponseHeaders(t *testing.T) {
 	type tt struct {
 		path    string
@@ -147,6 +237,24 @@ func TestAssertionsResponseHeaders(t *testing.T) {
 		err:    "failed asserting that '{}' is a valid response header (etag is required)",
 	})
 
+	tests.Add("canonical headers", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		headers: http.Header{
+			"Etag": {"value"},
+		},
+	})
+
+	tests.Add("raw headers", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		headers: map[string][]string{
+			"ETag": {"value"},
+		},
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		doc, _ := LoadFromURI("./fixtures/docs.json")
 		assertions := New(doc)
@@ -160,6 +268,8 @@ func TestAssertionsRequestQuery(t *testing.T) {
 	type tt struct {
 		path   string
 		method string
+		query  url.Values
+		opts   []Option
 		err    string
 	}
 
@@ -174,14 +284,36 @@ func TestAssertionsRequestQuery(t *testing.T) {
 	tests.Add("required values", tt{
 		path:   "/api/pets",
 		method: http.MethodGet,
+		query:  url.Values{},
 		err:    "failed asserting that '{}' is a valid request query (limit is required)",
 	})
 
+	tests.Add("undeclared", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		query:  url.Values{"limit": {"10"}, "page": {"1"}},
+	})
+
+	tests.Add("strict undeclared", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		query:  url.Values{"limit": {"10"}, "page": {"1"}},
+		opts:   []Option{WithStrict()},
+		err:    `failed asserting that '{"limit":["10"],"page":["1"]}' is a valid request query (page is not declared)`,
+	})
+
+	tests.Add("strict declared", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		query:  url.Values{"limit": {"10"}, "tags": {"a"}},
+		opts:   []Option{WithStrict()},
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		doc, _ := LoadFromURI("./fixtures/docs.json")
-		assertions := New(doc)
+		assertions := New(doc, tt.opts...)
 
-		err := assertions.RequestQuery(url.Values{}, tt.path, tt.method)
+		err := assertions.RequestQuery(tt.query, tt.path, tt.method)
 		testy.Error(t, tt.err, err)
 	})
 }
@@ -191,6 +323,7 @@ func TestAssertionsRequestBody(t *testing.T) {
 		path   string
 		method string
 		body   io.Reader
+		opts   []Option
 		err    string
 	}
 
@@ -210,11 +343,31 @@ func TestAssertionsRequestBody(t *testing.T) {
 		err:    "failed",
 	})
 
+	tests.Add("missing required body", tt{
+		path:   "/api/pets",
+		method: http.MethodPost,
+		body:   strings.NewReader(" "),
+		err:    "missing required body",
+	})
+
 	tests.Add("invalid data", tt{
 		path:   "/api/pets",
 		method: http.MethodPost,
+		body:   strings.NewReader("{"),
+		err:    "unexpected EOF",
+	})
+
+	tests.Add("empty optional body", tt{
+		path:   "/api/food",
+		method: http.MethodPost,
 		body:   strings.NewReader(""),
-		err:    "EOF",
+	})
+
+	tests.Add("optional body", tt{
+		path:   "/api/food",
+		method: http.MethodPost,
+		body:   strings.NewReader("{}"),
+		err:    "failed asserting that '{}' is a valid request body (name is required)",
 	})
 
 	tests.Add("required values", tt{
@@ -224,21 +377,207 @@ func TestAssertionsRequestBody(t *testing.T) {
 		err:    "failed asserting that '{}' is a valid request body (id is required, name is required, id is required, Must validate all the schemas (allOf))",
 	})
 
+	tests.Add("strict undeclared", tt{
+		path:   "/api/pets",
+		method: http.MethodPost,
+		body:   strings.NewReader(`{"id": 1, "name": "doggo", "color": "black"}`),
+		opts:   []Option{WithStrict()},
+		err:    `failed asserting that '{"id": 1, "name": "doggo", "color": "black"}' is a valid request body (/color is not declared)`,
+	})
+
+	tests.Add("strict declared", tt{
+		path:   "/api/pets",
+		method: http.MethodPost,
+		body:   strings.NewReader(`{"id": 1, "name": "doggo", "tag": "dog"}`),
+		opts:   []Option{WithStrict()},
+	})
+
+	tests.Add("too large", tt{
+		path:   "/api/pets",
+		method: http.MethodPost,
+		body:   strings.NewReader(`{"id": 1, "name": "doggo"}`),
+		opts:   []Option{WithMaxBodySize(10)},
+		err:    "body exceeds the maximum size",
+	})
+
+	tests.Add("within size", tt{
+		path:   "/api/pets",
+		method: http.MethodPost,
+		body:   strings.NewReader(`{"id": 1, "name": "doggo"}`),
+		opts:   []Option{WithMaxBodySize(26)},
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		doc, _ := LoadFromURI("./fixtures/docs.json")
-		assertions := New(doc)
+		assertions := New(doc, tt.opts...)
 
 		err := assertions.RequestBody(tt.body, tt.path, tt.method)
 		testy.Error(t, tt.err, err)
 	})
 }
 
+func TestAssertionsRequestBodyWithMediaType(t *testing.T) {
+	type tt struct {
+		mediaType string
+		body      io.Reader
+		opts      []Option
+		err       string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("invalid xml", tt{
+		mediaType: "application/xml",
+		body:      strings.NewReader(`<pet`),
+		err:       "XML syntax error on line 1: unexpected EOF",
+	})
+
+	tests.Add("invalid data", tt{
+		mediaType: "application/xml; charset=utf-8",
+		body:      strings.NewReader(`<pet id="a"></pet>`),
+		err:       `failed asserting that '<pet id="a"></pet>' is a valid request body (name is required, Invalid type. Expected: integer, given: string)`,
+	})
+
+	tests.Add("strict undeclared", tt{
+		mediaType: "text/xml",
+		body:      strings.NewReader(`<pet id="1" color="black"><full-name>doggo</full-name></pet>`),
+		opts:      []Option{WithStrict()},
+		err:       `failed asserting that '<pet id="1" color="black"><full-name>doggo</full-name></pet>' is a valid request body (/color is not declared)`,
+	})
+
+	tests.Add("json", tt{
+		mediaType: "application/json",
+		body:      strings.NewReader(`{"id": 1, "name": "doggo"}`),
+	})
+
+	tests.Add("success", tt{
+		mediaType: "application/xml",
+		body:      strings.NewReader(`<pet id="1"><full-name>doggo</full-name><tags><tag>a</tag></tags></pet>`),
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/xml.json")
+		assertions := New(doc, tt.opts...)
+
+		err := assertions.RequestBodyWithMediaType(tt.body, tt.mediaType, "/api/pets", http.MethodPost)
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestAssertionsResponseBodyWithMediaType(t *testing.T) {
+	type tt struct {
+		mediaType string
+		body      io.Reader
+		err       string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("invalid data", tt{
+		mediaType: "application/xml",
+		body:      strings.NewReader(`<pets><pet id="1"></pet></pets>`),
+		err:       `failed asserting that '<pets><pet id="1"></pet></pets>' is a valid response body (name is required)`,
+	})
+
+	tests.Add("success", tt{
+		mediaType: "application/xml",
+		body:      strings.NewReader(`<pets><pet id="1"><full-name>doggo</full-name></pet></pets>`),
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/xml.json")
+		assertions := New(doc)
+
+		err := assertions.ResponseBodyWithMediaType(tt.body, tt.mediaType, "/api/pets", http.MethodGet, http.StatusOK)
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestAssertionsBodyDecoders(t *testing.T) {
+	type tt struct {
+		path      string
+		status    int
+		mediaType string
+		body      io.Reader
+		opts      []Option
+		err       string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("json suffix", tt{
+		path:      "/api/pets",
+		status:    http.StatusOK,
+		mediaType: "application/vnd.pets+json",
+		body:      strings.NewReader(`{}`),
+		err:       "failed asserting that '{}' is a valid response body (Invalid type. Expected: array, given: object)",
+	})
+
+	tests.Add("text", tt{
+		path:      "/api/pets",
+		status:    http.StatusOK,
+		mediaType: "text/plain; charset=utf-8",
+		body:      strings.NewReader(`doggo`),
+		err:       "failed asserting that 'doggo' is a valid response body (Invalid type. Expected: array, given: string)",
+	})
+
+	tests.Add("binary", tt{
+		path:      "/api/pets/1/photo",
+		status:    http.StatusOK,
+		mediaType: "image/gif",
+		body:      strings.NewReader("GIF89a"),
+	})
+
+	tests.Add("empty binary", tt{
+		path:      "/api/pets/1/photo",
+		status:    http.StatusOK,
+		mediaType: "image/gif",
+		body:      strings.NewReader(""),
+		err:       "body is empty",
+	})
+
+	tests.Add("octet stream", tt{
+		path:      "/api/pets",
+		status:    http.StatusOK,
+		mediaType: "application/octet-stream",
+		body:      strings.NewReader("\x00\x01"),
+	})
+
+	tests.Add("custom decoder", tt{
+		path:      "/api/pets",
+		status:    http.StatusOK,
+		mediaType: "text/csv",
+		body:      strings.NewReader("doggo\nkitty"),
+		opts: []Option{
+			WithDecoder("text/csv", func(data []byte, _ Body) (interface{}, error) {
+				items := []interface{}{}
+
+				for _, name := range strings.Split(string(data), "\n") {
+					items = append(items, map[string]interface{}{"name": name})
+				}
+
+				return items, nil
+			}),
+		},
+		err: "failed asserting that 'doggo\nkitty' is a valid response body (id is required, id is required)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, tt.opts...)
+
+		err := assertions.ResponseBodyWithMediaType(tt.body, tt.mediaType, tt.path, http.MethodGet, tt.status)
+		testy.Error(t, tt.err, err)
+	})
+}
+
 func TestAssertionsResponseBody(t *testing.T) {
 	type tt struct {
 		path   string
 		method string
 		status int
 		body   io.Reader
+		opts   []Option
 		err    string
 	}
 
@@ -276,9 +615,62 @@ func TestAssertionsResponseBody(t *testing.T) {
 		err:    "failed asserting that '{}' is a valid response body (Invalid type. Expected: array, given: object)",
 	})
 
+	tests.Add("strict undeclared", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		body:   strings.NewReader(`[{"id": 1, "name": "doggo", "owner": {"name": "me"}}]`),
+		opts:   []Option{WithStrict()},
+		err:    `failed asserting that '[{"id": 1, "name": "doggo", "owner": {"name": "me"}}]' is a valid response body (/0/owner is not declared)`,
+	})
+
+	tests.Add("streaming", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		body:   strings.NewReader(` [{"id": 1, "name": "doggo"}, {"id": 2, "name": "kitty"}]`),
+		opts:   []Option{WithStreaming()},
+	})
+
+	tests.Add("streaming invalid item", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		body:   strings.NewReader(`[{"id": 1, "name": "doggo"}, {"id": 2}]`),
+		opts:   []Option{WithStreaming()},
+		err:    `failed asserting that '{"id": 2}' is a valid response body item /1 (name is required)`,
+	})
+
+	tests.Add("streaming strict", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		body:   strings.NewReader(`[{"id": 1, "name": "doggo", "age": 1}]`),
+		opts:   []Option{WithStreaming(), WithStrict()},
+		err:    `failed asserting that '{"id": 1, "name": "doggo", "age": 1}' is a valid response body item /0 (/age is not declared)`,
+	})
+
+	tests.Add("streaming not an array", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		body:   strings.NewReader(`{}`),
+		opts:   []Option{WithStreaming()},
+		err:    "failed asserting that '{}' is a valid response body (Invalid type. Expected: array, given: object)",
+	})
+
+	tests.Add("streaming too large", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		status: http.StatusOK,
+		body:   strings.NewReader(`[{"id": 1, "name": "doggo"}, {"id": 2, "name": "kitty"}]`),
+		opts:   []Option{WithStreaming(), WithMaxBodySize(32)},
+		err:    "body exceeds the maximum size",
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		doc, _ := LoadFromURI("./fixtures/docs.json")
-		assertions := New(doc)
+		assertions := New(doc, tt.opts...)
 
 		err := assertions.ResponseBody(tt.body, tt.path, tt.method, tt.status)
 		testy.Error(t, tt.err, err)
@@ -291,6 +683,7 @@ func TestAssertionsRequest(t *testing.T) {
 		method    string
 		mediaType string
 		body      io.Reader
+		opts      []Option
 		err       string
 	}
 
@@ -299,7 +692,7 @@ func TestAssertionsRequest(t *testing.T) {
 	tests.Add("without required headers", tt{
 		path:   "/api/pets/1",
 		method: http.MethodPatch,
-		err:    `failed asserting that '{"Content-Type":""}' is a valid request header (x-required-header is required)`,
+		err:    `failed asserting that '{"content-type":""}' is a valid request header (x-required-header is required)`,
 	})
 
 	tests.Add("without media type", tt{
@@ -310,6 +703,18 @@ func TestAssertionsRequest(t *testing.T) {
 		err:       "failed asserting that 'text/html' is an allowed media type (application/json)",
 	})
 
+	tests.Add("without media type nor body", tt{
+		path:   "/api/food",
+		method: http.MethodGet,
+		body:   http.NoBody,
+	})
+
+	tests.Add("without media type and empty body", tt{
+		path:   "/api/food",
+		method: http.MethodGet,
+		body:   bytes.NewBufferString(""),
+	})
+
 	tests.Add("without query", tt{
 		path:   "/api/pets",
 		method: http.MethodGet,
@@ -338,9 +743,70 @@ func TestAssertionsRequest(t *testing.T) {
 		body:      bytes.NewBufferString(`{"id": 1, "name": "doggo"}`),
 	})
 
+	tests.Add("missing required body", tt{
+		path:      "/api/pets",
+		method:    http.MethodPost,
+		mediaType: "application/json",
+		body:      bytes.NewBufferString(""),
+		err:       "missing required body",
+	})
+
+	tests.Add("empty optional body", tt{
+		path:      "/api/food",
+		method:    http.MethodPost,
+		mediaType: "application/json",
+		body:      bytes.NewBufferString(""),
+	})
+
+	tests.Add("with query string", tt{
+		path:      "/api/food?page=1",
+		method:    http.MethodGet,
+		mediaType: "application/json",
+		body:      bytes.NewBufferString("{}"),
+	})
+
+	tests.Add("with path prefix", tt{
+		path:      "/svc/api/food",
+		method:    http.MethodGet,
+		mediaType: "application/json",
+		body:      bytes.NewBufferString("{}"),
+		opts:      []Option{WithPathPrefix("/svc/")},
+	})
+
+	tests.Add("invalid host", tt{
+		path:   "http://example.com/api/food",
+		method: http.MethodGet,
+		opts:   []Option{WithServerValidation()},
+		err:    "failed asserting that 'example.com' is an allowed host (petstore.swagger.io)",
+	})
+
+	tests.Add("invalid scheme", tt{
+		path:   "https://petstore.swagger.io/api/food",
+		method: http.MethodGet,
+		opts:   []Option{WithServerValidation()},
+		err:    "failed asserting that 'https' is an allowed scheme (http)",
+	})
+
+	tests.Add("valid server", tt{
+		path:      "http://petstore.swagger.io:8080/api/food",
+		method:    http.MethodGet,
+		mediaType: "application/json",
+		body:      bytes.NewBufferString("{}"),
+		opts:      []Option{WithServerValidation()},
+	})
+
+	tests.Add("too large body", tt{
+		path:      "/api/pets",
+		method:    http.MethodPost,
+		mediaType: "application/json",
+		body:      bytes.NewBufferString(`{"id": 1, "name": "doggo"}`),
+		opts:      []Option{WithMaxBodySize(10)},
+		err:       "body exceeds the maximum size",
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		doc, _ := LoadFromURI("./fixtures/docs.json")
-		assertions := New(doc)
+		assertions := New(doc, tt.opts...)
 
 		req, _ := http.NewRequest(tt.method, tt.path, tt.body)
 		req.Header.Add("Content-Type", tt.mediaType)
@@ -354,6 +820,32 @@ func TestAssertionsRequest(t *testing.T) {
 	})
 }
 
+func TestAssertionsRequestTooLargeBody(t *testing.T) {
+	body := `{"id": 1, "name": "doggo"}`
+
+	for _, size := range []int64{1, 10, 25} {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, WithMaxBodySize(size))
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/pets", strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+
+		err := assertions.Request(req)
+		if !errors.Is(err, ErrBodyTooLarge) {
+			t.Fatalf("size %d: expected ErrBodyTooLarge, got %v", size, err)
+		}
+
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != body {
+			t.Errorf("size %d: expected the whole body %q, got %q", size, body, data)
+		}
+	}
+}
+
 func TestAssertionsResponse(t *testing.T) {
 	type tt struct {
 		path    string
@@ -393,7 +885,7 @@ func TestAssertionsResponse(t *testing.T) {
 			"etag":         {"value"},
 		},
 		body: ioutil.NopCloser(bytes.NewBufferString("{}")),
-		err:  "failed asserting that '{}' is a valid response body (Invalid type. Expected: array, given: object)",
+		err:  "failed asserting that '{}' is a valid response body (Invalid type. Expected: array, given: string)",
 	})
 
 	tests.Add("read body", tt{
@@ -407,6 +899,13 @@ func TestAssertionsResponse(t *testing.T) {
 		body: ioutil.NopCloser(bytes.NewBufferString(`[{"id": 1, "name": "doggo"}]`)),
 	})
 
+	tests.Add("without body", tt{
+		path:   "/api/pets/1",
+		method: http.MethodDelete,
+		status: http.StatusNoContent,
+		body:   http.NoBody,
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
 		req, _ := http.NewRequest(tt.method, tt.path, nil)
 		res := &http.Response{
@@ -427,3 +926,31 @@ func TestAssertionsResponse(t *testing.T) {
 		}
 	})
 }
+
+// plainDocument hides the optional interfaces of the document, like the
+// Document implementations outside the package.
+type plainDocument struct {
+	Document
+}
+
+func TestAssertionsPlainDocument(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+	assertions := New(plainDocument{doc})
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/pets", strings.NewReader(""))
+	req.Header.Add("Content-Type", "application/json")
+
+	if err := assertions.Request(req); err != nil {
+		t.Errorf("the body is optional when the document does not tell: %v", err)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com/api/food", nil)
+
+	if err := New(plainDocument{doc}, WithServerValidation()).Request(req); err != nil {
+		t.Errorf("the server is not validated when the document does not declare one: %v", err)
+	}
+
+	if err := assertions.RequestBodyFor("addPet", strings.NewReader("{}")); !errors.Is(err, ErrOperationsUnsupported) {
+		t.Errorf("expected ErrOperationsUnsupported, got %v", err)
+	}
+}
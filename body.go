@@ -0,0 +1,329 @@
+package assert
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// ErrBodyTooLarge returns an error when body exceeds the maximum size.
+	ErrBodyTooLarge = err("body exceeds the maximum size")
+
+	// ErrBodyRequired returns an error when a required body is missing.
+	ErrBodyRequired = err("missing required body")
+)
+
+// maxBytesReader reads from r until n bytes are consumed and fails with
+// ErrBodyTooLarge when there is more data. The byte read past the limit to
+// detect the overflow is returned along with the error, so the data read so
+// far followed by r is still the whole body.
+type maxBytesReader struct {
+	r io.Reader
+	n int64
+}
+
+func (m *maxBytesReader) Read(p []byte) (int, error) {
+	if m.n < 0 {
+		return 0, ErrBodyTooLarge
+	}
+
+	if int64(len(p)) > m.n+1 {
+		p = p[:m.n+1]
+	}
+
+	n, err := m.r.Read(p)
+	m.n -= int64(n)
+
+	if m.n < 0 {
+		return n, ErrBodyTooLarge
+	}
+
+	return n, err
+}
+
+// limit wraps the body with the maximum body size, when configured.
+func (a *Assertions) limit(body io.Reader) io.Reader {
+	if a.maxBodySize <= 0 {
+		return body
+	}
+
+	return &maxBytesReader{body, a.maxBodySize}
+}
+
+// readBody reads the whole body respecting the maximum body size, stopping
+// when the context is done.
+func (a *Assertions) readBody(ctx context.Context, body io.Reader) ([]byte, error) {
+	if body == nil {
+		return []byte{}, nil
+	}
+
+	return ioutil.ReadAll(&contextReader{ctx, a.limit(body)})
+}
+
+// contextReader fails with the context error once the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(p)
+}
+
+// assertRequestBody asserts the request body read from the reader, enforcing
+// its presence when required. Empty optional bodies are accepted.
+func (a *Assertions) assertRequestBody(ctx context.Context, body io.Reader, mediaType, path, method string) error {
+	schema, err := a.doc.RequestBody(path, method)
+	if err != nil {
+		return err
+	}
+
+	required, err := requestBodyRequired(a.doc, path, method)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(body)
+
+	if _, err := firstByte(reader); err == io.EOF {
+		return a.missingBody(ctx, required)
+	}
+
+	return a.assertBody(ctx, schema, reader, mediaType, "request body")
+}
+
+// missingBody returns the violation for an empty body.
+func (a *Assertions) missingBody(ctx context.Context, required bool) error {
+	if required {
+		return a.finding(ctx, CheckRequiredBody, ErrBodyRequired)
+	}
+
+	return nil
+}
+
+// bodyChecks are the checks of the body kinds.
+var bodyChecks = map[string]Check{
+	"request body":  CheckRequestBody,
+	"response body": CheckResponseBody,
+}
+
+// assertBody asserts the body read from the reader against the schema.
+func (a *Assertions) assertBody(ctx context.Context, schema Body, body io.Reader, mediaType, kind string) error {
+	if a.streaming && isJSON(mediaType) && itemsSchema(schema) != nil {
+		return a.assertBodyStream(ctx, schema, &contextReader{ctx, a.limit(body)}, kind)
+	}
+
+	data, err := a.readBody(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	return a.assertBodyBytes(ctx, schema, data, mediaType, kind)
+}
+
+// assertBodyBytes asserts an already buffered body against the schema,
+// decoding it with the decoder registered for the media type.
+func (a *Assertions) assertBodyBytes(ctx context.Context, schema Body, data []byte, mediaType, kind string) error {
+	if a.streaming && isJSON(mediaType) && itemsSchema(schema) != nil {
+		return a.assertBodyStream(ctx, schema, bytes.NewReader(data), kind)
+	}
+
+	value, err := a.decoder(mediaType, schema)(ctx, data, schema)
+	if err != nil {
+		return a.decodeFinding(ctx, kind, err)
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	return a.assertBodyValue(ctx, schema, data, value, kind)
+}
+
+// assertBodyValue asserts the decoded body value against the schema, using
+// the raw data in the failure message.
+func (a *Assertions) assertBodyValue(ctx context.Context, schema Body, data []byte, value interface{}, kind string) error {
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+	if err != nil {
+		return err
+	}
+
+	result, err := compiled.Validate(dataLoader(value))
+	if err != nil {
+		return a.decodeFinding(ctx, kind, err)
+	}
+
+	undeclared := []Issue{}
+
+	if a.strict && result.Valid() {
+		undeclared = undeclaredBody(schema, value, "")
+	}
+
+	return firstError(
+		a.finding(ctx, bodyChecks[kind], bodyViolation(data, kind, resultIssues("body", "", result))),
+		a.finding(ctx, CheckUndeclaredProperty, bodyViolation(data, kind, undeclared)),
+	)
+}
+
+// decodeFinding applies the severity of the body check to a failure to
+// decode the body, like malformed json. Failures to read the body, like a
+// body too large or a done context, are returned as is.
+func (a *Assertions) decodeFinding(ctx context.Context, kind string, err error) error {
+	if errors.Is(err, ErrBodyTooLarge) || ctx.Err() != nil {
+		return err
+	}
+
+	return a.finding(ctx, bodyChecks[kind], err)
+}
+
+// bodyViolation returns the failure for the issues of a body check, or nil
+// when there are none.
+func bodyViolation(data []byte, kind string, issues []Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	errs := strings.Join(issueMessages(issues), ", ")
+
+	return failIssues(issues, `'%s' is a valid %s (%s)`, string(data), kind, errs)
+}
+
+// assertBodyStream validates a json array item by item, without reading the
+// whole document into memory. Bodies that are not arrays are validated as a
+// whole.
+func (a *Assertions) assertBodyStream(ctx context.Context, schema Body, body io.Reader, kind string) error {
+	reader := bufio.NewReader(body)
+
+	if c, err := firstByte(reader); err != nil || c != '[' {
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+
+		return a.assertBodyValue(ctx, schema, data, data, kind)
+	}
+
+	array := schema.(*spec.Schema)
+	items := itemsSchema(schema)
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(items))
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(reader)
+
+	if _, err := decoder.Token(); err != nil {
+		return a.decodeFinding(ctx, kind, err)
+	}
+
+	var count int64
+
+	for ; decoder.More(); count++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var item json.RawMessage
+
+		if err := decoder.Decode(&item); err != nil {
+			return a.decodeFinding(ctx, kind, err)
+		}
+
+		result, err := compiled.Validate(gojsonschema.NewBytesLoader(item))
+		if err != nil {
+			return err
+		}
+
+		prefix := fmt.Sprintf("/%d", count)
+		undeclared := []Issue{}
+
+		if a.strict && result.Valid() {
+			undeclared = undeclaredBody(items, []byte(item), prefix)
+		}
+
+		err = firstError(
+			a.finding(ctx, bodyChecks[kind], itemViolation(item, kind, count, resultIssues("body", prefix, result))),
+			a.finding(ctx, CheckUndeclaredProperty, itemViolation(item, kind, count, undeclared)),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return a.decodeFinding(ctx, kind, err)
+	}
+
+	if array.MinItems != nil && count < *array.MinItems {
+		issue := Issue{Location: "body", Pointer: "", Keyword: "minItems", Message: fmt.Sprintf("Array must have at least %d items", *array.MinItems)}
+
+		return a.finding(ctx, bodyChecks[kind], failIssues([]Issue{issue}, `the %s has at least %d items (%d given)`, kind, *array.MinItems, count))
+	}
+
+	if array.MaxItems != nil && count > *array.MaxItems {
+		issue := Issue{Location: "body", Pointer: "", Keyword: "maxItems", Message: fmt.Sprintf("Array must have at most %d items", *array.MaxItems)}
+
+		return a.finding(ctx, bodyChecks[kind], failIssues([]Issue{issue}, `the %s has at most %d items (%d given)`, kind, *array.MaxItems, count))
+	}
+
+	return nil
+}
+
+// itemViolation returns the failure for the issues of a streamed array item,
+// or nil when there are none.
+func itemViolation(item []byte, kind string, index int64, issues []Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	errs := strings.Join(issueMessages(issues), ", ")
+
+	return failIssues(issues, `'%s' is a valid %s item /%d (%s)`, string(item), kind, index, errs)
+}
+
+// itemsSchema returns the items schema when the schema describes a plain
+// array, which can be validated item by item.
+func itemsSchema(schema Body) *spec.Schema {
+	s, ok := schema.(*spec.Schema)
+	if !ok || s == nil || !s.Type.Contains("array") || s.Items == nil {
+		return nil
+	}
+
+	if len(s.AllOf) > 0 || len(s.AnyOf) > 0 || len(s.OneOf) > 0 || s.Not != nil || s.UniqueItems {
+		return nil
+	}
+
+	return s.Items.Schema
+}
+
+// firstByte returns the first non white space byte without consuming it.
+func firstByte(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
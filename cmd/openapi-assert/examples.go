@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	assert "github.com/faabiosr/openapi-assert"
+)
+
+// runExamples validates the document examples, printing each mismatch.
+func runExamples(args []string, stdout, stderr io.Writer) int {
+	var load loadFlags
+
+	fs := newFlagSet("examples", stderr)
+	load.register(fs)
+
+	uri, ok := load.parse(fs, args)
+	if !ok {
+		return exitUsage
+	}
+
+	doc, err := load.load(uri, assert.WithExamples())
+	if err != nil {
+		fmt.Fprintf(stderr, "openapi-assert: %s\n", err)
+		return exitFailure
+	}
+
+	err = assert.ValidateExamples(doc)
+
+	var verr *assert.ValidationError
+	if errors.As(err, &verr) {
+		for _, issue := range verr.Issues {
+			fmt.Fprintf(stdout, "%s:%d: %s: %s\n", uri, issue.Line, issue.Pointer, issue.Message)
+		}
+
+		return exitFailure
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "openapi-assert: %s\n", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
@@ -0,0 +1,173 @@
+// Command openapi-assert checks OpenAPI documents and the traffic of the
+// APIs they describe.
+//
+// Usage:
+//
+//	openapi-assert <command> [flags] <document>
+//
+// The commands are:
+//
+//	examples    validate the document examples against their schemas
+//	mock        serve responses generated from the document
+//	proxy       assert the traffic between a client and a service
+//
+// The document is given as argument or with the -spec flag.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	assert "github.com/faabiosr/openapi-assert"
+)
+
+// Exit codes of the commands.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// command runs a subcommand with its arguments, returning the exit code.
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"examples": {"validate the document examples against their schemas", runExamples},
+	"mock":     {"serve responses generated from the document", runMock},
+	"proxy":    {"assert the traffic between a client and a service", runProxy},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "openapi-assert: unknown command %q\n", args[0])
+		usage(stderr)
+
+		return exitUsage
+	}
+
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: openapi-assert <command> [flags] <document>")
+	fmt.Fprintln(w, "\ncommands:")
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s  %s\n", name, commands[name].summary)
+	}
+}
+
+// newFlagSet returns the flag set of the command, printing its errors and
+// usage to the writer.
+func newFlagSet(name string, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() {
+		fmt.Fprintf(w, "usage: openapi-assert %s [flags] <document>\n", name)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// loadFlags are the flags of the commands loading a document.
+type loadFlags struct {
+	spec    string
+	offline bool
+}
+
+func (f *loadFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.spec, "spec", "", "the document `uri`, instead of the argument")
+	fs.BoolVar(&f.offline, "offline", false, "forbid loading remote references")
+}
+
+// load loads the document at the uri with the options, as configured by the
+// flags.
+func (f *loadFlags) load(uri string, opts ...assert.LoadOption) (assert.Document, error) {
+	if f.offline {
+		opts = append(opts, assert.WithoutNetwork())
+	}
+
+	return assert.LoadFromURI(uri, opts...)
+}
+
+// parse parses the flags of the command, returning the document uri given
+// either by the -spec flag or as single argument.
+func (f *loadFlags) parse(fs *flag.FlagSet, args []string) (string, bool) {
+	if err := fs.Parse(args); err != nil {
+		return "", false
+	}
+
+	switch {
+	case f.spec != "" && fs.NArg() == 0:
+		return f.spec, true
+	case f.spec == "" && fs.NArg() == 1:
+		return fs.Arg(0), true
+	}
+
+	fs.Usage()
+
+	return "", false
+}
+
+// serve serves the handler on the address until interrupted, shutting down
+// gracefully.
+func serve(addr string, handler http.Handler, stdout, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		<-ctx.Done()
+
+		c, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_ = srv.Shutdown(c)
+	}()
+
+	fmt.Fprintf(stdout, "listening on %s\n", addr)
+
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "openapi-assert: %s\n", err)
+		return exitFailure
+	}
+
+	<-done
+
+	return exitOK
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestRun(t *testing.T) {
+	type tt struct {
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("no command", tt{
+		code: exitUsage,
+		stderr: `usage: openapi-assert <command> [flags] <document>
+
+commands:
+  examples    validate the document examples against their schemas
+  mock        serve responses generated from the document
+  proxy       assert the traffic between a client and a service
+`,
+	})
+
+	tests.Add("unknown command", tt{
+		args: []string{"unknown"},
+		code: exitUsage,
+		stderr: `openapi-assert: unknown command "unknown"
+usage: openapi-assert <command> [flags] <document>
+
+commands:
+  examples    validate the document examples against their schemas
+  mock        serve responses generated from the document
+  proxy       assert the traffic between a client and a service
+`,
+	})
+
+	tests.Add("examples without document", tt{
+		args: []string{"examples"},
+		code: exitUsage,
+		stderr: `usage: openapi-assert examples [flags] <document>
+  -offline
+    	forbid loading remote references
+  -spec uri
+    	the document uri, instead of the argument
+`,
+	})
+
+	tests.Add("examples document not found", tt{
+		args:   []string{"examples", "../../fixtures/unknown.json"},
+		code:   exitFailure,
+		stderr: "openapi-assert: unable to load the document by uri: open ../../fixtures/unknown.json: no such file or directory\n",
+	})
+
+	tests.Add("valid examples", tt{
+		args: []string{"examples", "-offline", "../../fixtures/docs.json"},
+	})
+
+	tests.Add("examples with spec flag", tt{
+		args: []string{"examples", "-spec", "../../fixtures/docs.json"},
+	})
+
+	tests.Add("mock without document", tt{
+		args: []string{"mock", "-addr", ":0"},
+		code: exitUsage,
+		stderr: `usage: openapi-assert mock [flags] <document>
+  -addr address
+    	the address to listen on (default ":8080")
+  -offline
+    	forbid loading remote references
+  -seed int
+    	the seed of the generated payloads (default 1)
+  -spec uri
+    	the document uri, instead of the argument
+`,
+	})
+
+	tests.Add("proxy without upstream", tt{
+		args:   []string{"proxy", "-spec", "../../fixtures/docs.json"},
+		code:   exitUsage,
+		stderr: "openapi-assert: invalid upstream \"\"\n",
+	})
+
+	tests.Add("invalid examples", tt{
+		args: []string{"examples", "../../fixtures/examples.json"},
+		code: exitFailure,
+		stdout: `../../fixtures/examples.json:18: /paths/~1pets~1{id}/parameters/0/example: Invalid type. Expected: integer, given: string
+../../fixtures/examples.json:37: /paths/~1pets~1{id}/get/responses/200/headers/X-Rate-Limit/example: Must be greater than or equal to 1
+../../fixtures/examples.json:54: /paths/~1pets~1{id}/get/responses/default/examples/application~1problem+json/code: code is required
+../../fixtures/examples.json:86: /parameters/limit/example: Must be less than or equal to 100
+../../fixtures/examples.json:106: /definitions/Pet/properties/tag/example: Invalid type. Expected: string, given: integer
+../../fixtures/examples.json:109: /definitions/Pet/example/name: name is required
+../../fixtures/examples.json:110: /definitions/Pet/example/id: Invalid type. Expected: integer, given: string
+`,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		var stdout, stderr bytes.Buffer
+
+		code := run(tt.args, &stdout, &stderr)
+
+		if code != tt.code {
+			t.Errorf("unexpected exit code %d, expected %d", code, tt.code)
+		}
+
+		if d := testy.DiffText(tt.stdout, stdout.String()); d != nil {
+			t.Errorf("stdout: %s", d)
+		}
+
+		if d := testy.DiffText(tt.stderr, stderr.String()); d != nil {
+			t.Errorf("stderr: %s", d)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	assert "github.com/faabiosr/openapi-assert"
+	assertecho "github.com/faabiosr/openapi-assert/middleware/echo"
+)
+
+// runMock serves the document operations, answering the valid requests with
+// the examples or generated payloads.
+func runMock(args []string, stdout, stderr io.Writer) int {
+	var load loadFlags
+
+	fs := newFlagSet("mock", stderr)
+	load.register(fs)
+
+	addr := fs.String("addr", ":8080", "the `address` to listen on")
+	seed := fs.Int64("seed", 1, "the seed of the generated payloads")
+
+	uri, ok := load.parse(fs, args)
+	if !ok {
+		return exitUsage
+	}
+
+	doc, err := load.load(uri)
+	if err != nil {
+		fmt.Fprintf(stderr, "openapi-assert: %s\n", err)
+		return exitFailure
+	}
+
+	return serve(*addr, newMock(doc, *seed), stdout, stderr)
+}
+
+// mock answers the requests asserted by the middleware with generated
+// responses.
+type mock struct {
+	doc assert.Document
+
+	mu  sync.Mutex
+	gen *assert.Generator
+}
+
+// newMock returns the mock server of the document. The violations are
+// responded as problem details.
+func newMock(doc assert.Document, seed int64) *echo.Echo {
+	m := &mock{doc: doc, gen: assert.NewGenerator(seed)}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	e.Use(assertecho.AssertWithConfig(assertecho.AssertConfig{
+		Document:    doc,
+		Deprecation: true,
+	}))
+
+	e.Any("/*", m.handle)
+
+	return e
+}
+
+// handle responds the status code preferred by the request, or the first
+// successful one declared.
+func (m *mock) handle(ctx echo.Context) error {
+	op, ok := assertecho.OperationFrom(ctx)
+	if !ok {
+		return echo.ErrNotFound
+	}
+
+	req := ctx.Request()
+
+	code, ok := preferredStatus(req.Header.Get("Prefer"))
+	if !ok {
+		code = defaultStatus(op)
+	}
+
+	mediaType := negotiate(req.Header.Get("Accept"), op.Produces)
+
+	m.mu.Lock()
+	res, err := m.gen.Response(m.doc, req.URL.Path, req.Method, code, mediaType)
+	m.mu.Unlock()
+
+	if err != nil {
+		return assertecho.ProblemHandler(ctx, err)
+	}
+
+	for name, values := range res.Header {
+		ctx.Response().Header()[name] = values
+	}
+
+	switch body := res.Body.(type) {
+	case nil:
+		return ctx.NoContent(res.StatusCode)
+	case string:
+		if !isJSON(mediaType) {
+			return ctx.Blob(res.StatusCode, mediaType, []byte(body))
+		}
+	}
+
+	data, err := json.Marshal(res.Body)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Blob(res.StatusCode, mediaType, data)
+}
+
+// preferredStatus returns the status code of the code preference, like
+// "Prefer: code=404".
+func preferredStatus(prefer string) (int, bool) {
+	for _, pref := range strings.FieldsFunc(prefer, func(r rune) bool { return r == ',' || r == ';' }) {
+		name, value, ok := cut(strings.TrimSpace(pref), "=")
+		if !ok || !strings.EqualFold(name, "code") {
+			continue
+		}
+
+		if code, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+			return code, true
+		}
+	}
+
+	return 0, false
+}
+
+// defaultStatus returns the first successful status code of the operation,
+// the first declared otherwise, or 200 for a default response only.
+func defaultStatus(op *assert.Operation) int {
+	codes := op.StatusCodes()
+
+	for _, code := range codes {
+		if code >= 200 && code < 300 {
+			return code
+		}
+	}
+
+	if len(codes) > 0 {
+		return codes[0]
+	}
+
+	return http.StatusOK
+}
+
+// negotiate returns the first produced media type accepted, or the first
+// produced one when the request accepts any.
+func negotiate(accept string, produces []string) string {
+	if len(produces) == 0 {
+		return echo.MIMEApplicationJSON
+	}
+
+	if accept == "" {
+		return produces[0]
+	}
+
+	for _, p := range produces {
+		mediaType, _, err := mime.ParseMediaType(p)
+		if err != nil {
+			continue
+		}
+
+		for _, a := range strings.Split(accept, ",") {
+			accepted, _, err := mime.ParseMediaType(strings.TrimSpace(a))
+			if err != nil {
+				continue
+			}
+
+			if accepted == "*/*" || accepted == mediaType ||
+				(strings.HasSuffix(accepted, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(accepted, "*"))) {
+				return p
+			}
+		}
+	}
+
+	return produces[0]
+}
+
+func isJSON(mediaType string) bool {
+	mt, _, _ := mime.ParseMediaType(mediaType)
+	return strings.HasSuffix(mt, "json")
+}
+
+// cut slices s around the first instance of sep.
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
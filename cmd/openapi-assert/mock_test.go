@@ -0,0 +1,157 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	assert "github.com/faabiosr/openapi-assert"
+)
+
+func TestMock(t *testing.T) {
+	type tt struct {
+		doc     string
+		method  string
+		target  string
+		header  http.Header
+		payload string
+		status  int
+		ctype   string
+		body    string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("example", tt{
+		doc:    "../../fixtures/examples.json",
+		method: http.MethodGet,
+		target: "/api/pets/1",
+		status: http.StatusOK,
+		ctype:  "application/json",
+		body:   `{"id":1,"name":"doggo"}`,
+	})
+
+	tests.Add("accepted example", tt{
+		doc:    "../../fixtures/examples.json",
+		method: http.MethodGet,
+		target: "/api/pets/1",
+		header: http.Header{"Accept": {"text/*"}},
+		status: http.StatusOK,
+		ctype:  "text/plain",
+		body:   "doggo",
+	})
+
+	tests.Add("preferred status", tt{
+		doc:    "../../fixtures/examples.json",
+		method: http.MethodGet,
+		target: "/api/pets/1",
+		header: http.Header{"Prefer": {"code=500"}},
+		status: http.StatusInternalServerError,
+		ctype:  "application/json",
+	})
+
+	tests.Add("generated", tt{
+		doc:     "../../fixtures/docs.json",
+		method:  http.MethodPost,
+		target:  "/api/pets",
+		header:  http.Header{"Content-Type": {"application/json"}},
+		payload: `{"id":1,"name":"doggo"}`,
+		status:  http.StatusOK,
+		ctype:   "application/json",
+	})
+
+	tests.Add("no content", tt{
+		doc:     "../../fixtures/examples.json",
+		method:  http.MethodPut,
+		target:  "/api/pets/1",
+		header:  http.Header{"Content-Type": {"application/json"}},
+		payload: `{"id":1,"name":"doggo"}`,
+		status:  http.StatusNoContent,
+		ctype:   "application/json",
+	})
+
+	tests.Add("undeclared status", tt{
+		doc:     "../../fixtures/examples.json",
+		method:  http.MethodPut,
+		target:  "/api/pets/1",
+		header:  http.Header{"Content-Type": {"application/json"}, "Prefer": {"code=500"}},
+		payload: `{"id":1,"name":"doggo"}`,
+		status:  http.StatusBadRequest,
+		ctype:   "application/problem+json",
+	})
+
+	tests.Add("invalid request", tt{
+		doc:     "../../fixtures/examples.json",
+		method:  http.MethodPut,
+		target:  "/api/pets/1",
+		header:  http.Header{"Content-Type": {"application/json"}},
+		payload: `{"id":"1"}`,
+		status:  http.StatusBadRequest,
+		ctype:   "application/problem+json",
+	})
+
+	tests.Add("unknown path", tt{
+		doc:    "../../fixtures/examples.json",
+		method: http.MethodGet,
+		target: "/api/food",
+		status: http.StatusNotFound,
+		ctype:  "application/problem+json",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, err := assert.LoadFromURI(tt.doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.payload))
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+
+		rec := httptest.NewRecorder()
+
+		newMock(doc, 1).ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("unexpected status %d: %s", rec.Code, rec.Body)
+		}
+
+		if ctype := rec.Header().Get("Content-Type"); ctype != tt.ctype {
+			t.Errorf("unexpected content type %q", ctype)
+		}
+
+		if tt.status >= http.StatusBadRequest || tt.method != http.MethodGet {
+			return
+		}
+
+		if d := testy.DiffText(tt.body, rec.Body.String()); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestPreferredStatus(t *testing.T) {
+	type tt struct {
+		prefer string
+		code   int
+		ok     bool
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("empty", tt{})
+	tests.Add("code", tt{prefer: "code=404", code: 404, ok: true})
+	tests.Add("quoted", tt{prefer: `return=minimal, code="201"`, code: 201, ok: true})
+	tests.Add("invalid", tt{prefer: "code=abc"})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		code, ok := preferredStatus(tt.prefer)
+		if code != tt.code || ok != tt.ok {
+			t.Errorf("unexpected status %d, %v", code, ok)
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	assert "github.com/faabiosr/openapi-assert"
+	assertecho "github.com/faabiosr/openapi-assert/middleware/echo"
+)
+
+// runProxy forwards the requests to the upstream, asserting the requests
+// and responses, and writes a summary of the violations on shutdown.
+func runProxy(args []string, stdout, stderr io.Writer) int {
+	var load loadFlags
+
+	fs := newFlagSet("proxy", stderr)
+	load.register(fs)
+
+	addr := fs.String("addr", ":8080", "the `address` to listen on")
+	upstream := fs.String("upstream", "", "the `url` of the service")
+	block := fs.Bool("block", false, "block the violations instead of logging them")
+
+	uri, ok := load.parse(fs, args)
+	if !ok {
+		return exitUsage
+	}
+
+	target, err := url.Parse(*upstream)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		fmt.Fprintf(stderr, "openapi-assert: invalid upstream %q\n", *upstream)
+		return exitUsage
+	}
+
+	doc, err := load.load(uri)
+	if err != nil {
+		fmt.Fprintf(stderr, "openapi-assert: %s\n", err)
+		return exitFailure
+	}
+
+	p := newProxy(doc, target, *block, log.New(stderr, "", log.LstdFlags))
+
+	code := serve(*addr, p, stdout, stderr)
+	p.summary(stdout)
+
+	return code
+}
+
+// inboundKey stores the request received by the proxy in the context of the
+// request forwarded, to assert the response against the original path.
+type inboundKey struct{}
+
+// proxy is a reverse proxy asserting the requests and responses.
+type proxy struct {
+	assertions *assert.Assertions
+	upstream   *httputil.ReverseProxy
+	block      bool
+	log        *log.Logger
+
+	mu         sync.Mutex
+	requests   int
+	violations map[string]*violations
+}
+
+// violations counts the violations of an operation.
+type violations struct {
+	requests  int
+	responses int
+}
+
+// newProxy returns the proxy of the document to the upstream. When blocking,
+// the invalid requests are responded as problem details without reaching the
+// upstream, and the invalid responses are replaced by a 502 problem.
+func newProxy(doc assert.Document, upstream *url.URL, block bool, logger *log.Logger) *proxy {
+	p := &proxy{
+		assertions: assert.New(doc),
+		upstream:   httputil.NewSingleHostReverseProxy(upstream),
+		block:      block,
+		log:        logger,
+		violations: map[string]*violations{},
+	}
+
+	p.upstream.ModifyResponse = p.assertResponse
+	p.upstream.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		problem(w, err, http.StatusBadGateway)
+	}
+
+	return p
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.requests++
+	p.mu.Unlock()
+
+	if err := p.assertions.Request(r); err != nil {
+		p.violation(r, err, func(v *violations) { v.requests++ })
+
+		if p.block {
+			problem(w, err, assertecho.StatusCode(err))
+			return
+		}
+	}
+
+	if p.declared(r) {
+		inbound := r.Clone(r.Context())
+		r = r.WithContext(context.WithValue(r.Context(), inboundKey{}, inbound))
+	}
+
+	p.upstream.ServeHTTP(w, r)
+}
+
+// declared reports whether the document declares the operation of the
+// request, whose response can then be asserted. Documents not describing
+// their operations declare every request.
+func (p *proxy) declared(r *http.Request) bool {
+	_, err := p.assertions.Operation(r)
+
+	return err == nil || errors.Is(err, assert.ErrOperationsUnsupported)
+}
+
+// assertResponse asserts the upstream response against the request received
+// by the proxy, failing the response when blocking. The responses of the
+// requests to undeclared operations are not asserted, as their request
+// violation is already counted. Compressed bodies are asserted decoded, and
+// forwarded unchanged.
+func (p *proxy) assertResponse(res *http.Response) error {
+	inbound, ok := res.Request.Context().Value(inboundKey{}).(*http.Request)
+	if !ok {
+		return nil
+	}
+
+	asserted, err := decodeResponse(res)
+	if err == nil {
+		asserted.Request = inbound
+		err = p.assertions.Response(asserted)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	p.violation(inbound, err, func(v *violations) { v.responses++ })
+
+	if p.block {
+		return err
+	}
+
+	return nil
+}
+
+// decodeResponse buffers the response body, to be forwarded unchanged, and
+// returns a copy of the response whose body is decoded according to its gzip
+// or deflate content encoding.
+func decodeResponse(res *http.Response) (*http.Response, error) {
+	if res.Body == nil || res.Body == http.NoBody {
+		decoded := *res
+		return &decoded, nil
+	}
+
+	raw, err := ioutil.ReadAll(res.Body)
+	_ = res.Body.Close()
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(raw))
+
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := *res
+	decoded.Body = ioutil.NopCloser(bytes.NewReader(raw))
+
+	var body io.ReadCloser
+
+	switch strings.ToLower(res.Header.Get("Content-Encoding")) {
+	case "gzip", "x-gzip":
+		body, err = gzip.NewReader(bytes.NewReader(raw))
+	case "deflate":
+		body, err = zlib.NewReader(bytes.NewReader(raw))
+	default:
+		return &decoded, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	decoded.Body = body
+	decoded.ContentLength = -1
+	decoded.Header = res.Header.Clone()
+	decoded.Header.Del("Content-Encoding")
+	decoded.Header.Del("Content-Length")
+
+	return &decoded, nil
+}
+
+// violation logs and counts the violation of the request operation.
+func (p *proxy) violation(r *http.Request, err error, count func(*violations)) {
+	key := r.Method + " " + r.URL.Path
+
+	if op, e := p.assertions.Operation(r); e == nil {
+		key = op.String()
+	}
+
+	p.log.Printf("%s %s: %s", r.Method, r.URL.RequestURI(), err)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	v, ok := p.violations[key]
+	if !ok {
+		v = &violations{}
+		p.violations[key] = v
+	}
+
+	count(v)
+}
+
+// summary writes the number of requests and the violations by operation.
+func (p *proxy) summary(w io.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := []string{}
+	total := violations{}
+
+	for key, v := range p.violations {
+		keys = append(keys, key)
+		total.requests += v.requests
+		total.responses += v.responses
+	}
+
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "requests: %d\n", p.requests)
+	fmt.Fprintf(w, "request violations: %d\n", total.requests)
+	fmt.Fprintf(w, "response violations: %d\n", total.responses)
+
+	for _, key := range keys {
+		v := p.violations[key]
+		fmt.Fprintf(w, "  %s: %d request, %d response\n", key, v.requests, v.responses)
+	}
+}
+
+// problem responds the error as problem details with the status code.
+func problem(w http.ResponseWriter, err error, status int) {
+	pb := assertecho.NewProblem(err)
+	pb.Status = status
+	pb.Title = http.StatusText(status)
+
+	data, e := json.Marshal(pb)
+	if e != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", assertecho.ProblemMediaType)
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
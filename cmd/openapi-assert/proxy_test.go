@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+
+	assert "github.com/faabiosr/openapi-assert"
+)
+
+func TestProxy(t *testing.T) {
+	type tt struct {
+		block  bool
+		method string
+		target string
+		body   string
+		status int
+		ctype  string
+		hits   int
+		logs   string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("valid traffic", tt{
+		method: http.MethodGet,
+		target: "/api/pets?limit=1",
+		status: http.StatusOK,
+		ctype:  "application/json",
+		hits:   1,
+	})
+
+	tests.Add("logged request violation", tt{
+		method: http.MethodPatch,
+		target: "/api/pets/1",
+		body:   `{"id": 1, "name": "doggo"}`,
+		status: http.StatusOK,
+		ctype:  "application/json",
+		hits:   1,
+		logs:   `PATCH /api/pets/1: failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)` + "\n",
+	})
+
+	tests.Add("blocked request violation", tt{
+		block:  true,
+		method: http.MethodPatch,
+		target: "/api/pets/1",
+		body:   `{"id": 1, "name": "doggo"}`,
+		status: http.StatusBadRequest,
+		ctype:  "application/problem+json",
+		logs:   `PATCH /api/pets/1: failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)` + "\n",
+	})
+
+	tests.Add("logged response violation", tt{
+		method: http.MethodGet,
+		target: "/api/pets/1",
+		status: http.StatusOK,
+		ctype:  "application/json",
+		hits:   1,
+		logs:   `GET /api/pets/1: failed asserting that '{"id":"1"}' is a valid response body (name is required, Invalid type. Expected: integer, given: string)` + "\n",
+	})
+
+	tests.Add("blocked response violation", tt{
+		block:  true,
+		method: http.MethodGet,
+		target: "/api/pets/1",
+		status: http.StatusBadGateway,
+		ctype:  "application/problem+json",
+		hits:   1,
+		logs:   `GET /api/pets/1: failed asserting that '{"id":"1"}' is a valid response body (name is required, Invalid type. Expected: integer, given: string)` + "\n",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		hits := 0
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Etag", "1")
+
+			switch {
+			case r.URL.Path == "/api/pets":
+				_, _ = w.Write([]byte(`[{"id": 1, "name": "doggo"}]`))
+			case r.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`{"id":"1"}`))
+			default:
+				_, _ = w.Write([]byte(`{"id": 1, "name": "doggo"}`))
+			}
+		}))
+		defer upstream.Close()
+
+		doc, _ := assert.LoadFromURI("../../fixtures/docs.json")
+		target, _ := url.Parse(upstream.URL)
+
+		var logs bytes.Buffer
+
+		p := newProxy(doc, target, tt.block, log.New(&logs, "", 0))
+
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		rec := httptest.NewRecorder()
+
+		p.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("unexpected status %d: %s", rec.Code, rec.Body)
+		}
+
+		if ctype := rec.Header().Get("Content-Type"); ctype != tt.ctype {
+			t.Errorf("unexpected content type %q", ctype)
+		}
+
+		if hits != tt.hits {
+			t.Errorf("unexpected upstream hits %d", hits)
+		}
+
+		if d := testy.DiffText(tt.logs, logs.String()); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestProxyContentEncoding(t *testing.T) {
+	type tt struct {
+		encoding string
+		writer   func(io.Writer) io.WriteCloser
+		body     string
+		logs     string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("gzip", tt{
+		encoding: "gzip",
+		writer:   func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		body:     `[{"id": 1, "name": "doggo"}]`,
+	})
+
+	tests.Add("deflate", tt{
+		encoding: "deflate",
+		writer:   func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		body:     `[{"id": 1, "name": "doggo"}]`,
+	})
+
+	tests.Add("gzip violation", tt{
+		encoding: "gzip",
+		writer:   func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		body:     `[{"id": "1"}]`,
+		logs:     `GET /api/pets?limit=1: failed asserting that '[{"id": "1"}]' is a valid response body (name is required, Invalid type. Expected: integer, given: string)` + "\n",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		var compressed bytes.Buffer
+
+		w := tt.writer(&compressed)
+		_, _ = w.Write([]byte(tt.body))
+		_ = w.Close()
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", tt.encoding)
+			w.Header().Set("Etag", "1")
+			_, _ = w.Write(compressed.Bytes())
+		}))
+		defer upstream.Close()
+
+		doc, _ := assert.LoadFromURI("../../fixtures/docs.json")
+		target, _ := url.Parse(upstream.URL)
+
+		var logs bytes.Buffer
+
+		p := newProxy(doc, target, false, log.New(&logs, "", 0))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/pets?limit=1", nil)
+		req.Header.Set("Accept-Encoding", tt.encoding)
+		rec := httptest.NewRecorder()
+
+		p.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("unexpected status %d: %s", rec.Code, rec.Body)
+		}
+
+		if encoding := rec.Header().Get("Content-Encoding"); encoding != tt.encoding {
+			t.Errorf("unexpected content encoding %q", encoding)
+		}
+
+		if !bytes.Equal(compressed.Bytes(), rec.Body.Bytes()) {
+			t.Error("expected the compressed body to be forwarded unchanged")
+		}
+
+		if d := testy.DiffText(tt.logs, logs.String()); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestProxySummary(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer upstream.Close()
+
+	doc, _ := assert.LoadFromURI("../../fixtures/docs.json")
+	target, _ := url.Parse(upstream.URL)
+
+	var logs bytes.Buffer
+
+	p := newProxy(doc, target, false, log.New(&logs, "", 0))
+
+	for _, r := range []struct{ method, target string }{
+		{http.MethodGet, "/api/pets/1"},
+		{http.MethodPatch, "/api/pets/1"},
+		{http.MethodGet, "/api/unknown"},
+	} {
+		req := httptest.NewRequest(r.method, r.target, nil)
+		if r.method == http.MethodPatch {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		p.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var summary bytes.Buffer
+	p.summary(&summary)
+
+	expected := `requests: 3
+request violations: 2
+response violations: 2
+  GET /api/pets/{id} (findPetById): 0 request, 1 response
+  GET /api/unknown: 1 request, 0 response
+  PATCH /api/pets/{id} (updatePet): 1 request, 1 response
+`
+
+	if d := testy.DiffText(expected, summary.String()); d != nil {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,333 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNoDocument returns an error when no member document claims the request.
+const ErrNoDocument = err("no document claims the request")
+
+// Resolver is implemented by the documents resolving another document for
+// each request, like the Composite. Assertions use the resolved document to
+// assert the request and its response.
+type Resolver interface {
+	Resolve(req *http.Request) (Document, error)
+}
+
+// Route claims the requests matching all its conditions for a document.
+// Empty conditions match every request.
+type Route struct {
+	// PathPrefix matches the request paths under the prefix.
+	PathPrefix string
+
+	// StripPrefix removes the path prefix before looking up the path in the
+	// document, for documents not aware of the prefix.
+	StripPrefix bool
+
+	// Host matches the request host, ignoring the port when not declared.
+	Host string
+
+	// Header and Value match the requests sending the header value, like an
+	// api version header.
+	Header string
+	Value  string
+
+	// Document is the document of the claimed requests.
+	Document Document
+}
+
+// Composite is a Document dispatching to several documents, by path prefix,
+// host or header. The Assertions resolve the document using the whole
+// request, while the Document methods only know the path and method: they use
+// the single route matching the path prefix and declaring the operation, and
+// fail with ErrNoDocument when several routes do.
+type Composite struct {
+	routes []Route
+}
+
+// NewComposite returns a Composite of the routes, matched in order.
+func NewComposite(routes ...Route) *Composite {
+	c := &Composite{}
+
+	for _, route := range routes {
+		route.PathPrefix = strings.TrimSuffix(route.PathPrefix, "/")
+		c.routes = append(c.routes, route)
+	}
+
+	return c
+}
+
+// Resolve returns the document of the first route matching the request.
+func (c *Composite) Resolve(req *http.Request) (Document, error) {
+	for _, route := range c.routes {
+		if !route.matchesPath(req.URL.Path) {
+			continue
+		}
+
+		if route.Host != "" && !hostMatches(requestHost(req), route.Host) {
+			continue
+		}
+
+		if route.Header != "" && req.Header.Get(route.Header) != route.Value {
+			continue
+		}
+
+		return route.document(), nil
+	}
+
+	return nil, fmt.Errorf("%w (%s %s)", ErrNoDocument, req.Method, req.URL.Path)
+}
+
+// RequestMediaTypes retrives a list of request media types allowed.
+func (c *Composite) RequestMediaTypes(path, method string) ([]string, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return doc.RequestMediaTypes(path, method)
+}
+
+// ResponseMediaTypes retrives a list of response media types allowed.
+func (c *Composite) ResponseMediaTypes(path, method string) ([]string, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return doc.ResponseMediaTypes(path, method)
+}
+
+// RequestHeaders retrieves a list of request headers.
+func (c *Composite) RequestHeaders(path, method string) (Headers, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.RequestHeaders(path, method)
+}
+
+// ResponseHeaders retrieves a list of response headers.
+func (c *Composite) ResponseHeaders(path, method string, statusCode int) (Headers, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.ResponseHeaders(path, method, statusCode)
+}
+
+// RequestQuery retrieves a list of request query.
+func (c *Composite) RequestQuery(path, method string) (Query, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.RequestQuery(path, method)
+}
+
+// RequestBody retrieves the request body.
+func (c *Composite) RequestBody(path, method string) (Body, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.RequestBody(path, method)
+}
+
+// RequestBodyRequired retrieves whether the request body is required.
+func (c *Composite) RequestBodyRequired(path, method string) (bool, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return false, err
+	}
+
+	return requestBodyRequired(doc, path, method)
+}
+
+// ResponseBody retrieves the response body.
+func (c *Composite) ResponseBody(path, method string, statusCode int) (Body, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.ResponseBody(path, method, statusCode)
+}
+
+// Operation retrieves the operation of the path and method.
+func (c *Composite) Operation(path, method string) (*Operation, error) {
+	doc, err := c.route(path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := documentOperation(doc, path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	return op.from(doc), nil
+}
+
+// Operations retrieves the operations of all the documents, in route order.
+// The operations remember their document, so the assertions by operationId
+// use it whatever the route conditions.
+func (c *Composite) Operations() []*Operation {
+	ops := []*Operation{}
+
+	for _, route := range c.routes {
+		doc := route.document()
+		routeOps, _ := documentOperations(doc)
+
+		for _, op := range routeOps {
+			ops = append(ops, op.from(doc))
+		}
+	}
+
+	return ops
+}
+
+// route returns the document of the route matching the path prefix and
+// declaring the operation, or of the first route matching the path prefix
+// when none declares it. Routes after the first one without host nor header
+// conditions are never matched.
+func (c *Composite) route(path, method string) (Document, error) {
+	matches := []Document{}
+	declaring := []Document{}
+
+	for _, route := range c.routes {
+		if !route.matchesPath(path) {
+			continue
+		}
+
+		doc := route.document()
+		matches = append(matches, doc)
+
+		if _, err := documentOperation(doc, path, method); err == nil || errors.Is(err, ErrOperationsUnsupported) {
+			declaring = append(declaring, doc)
+		}
+
+		if route.Host == "" && route.Header == "" {
+			break
+		}
+	}
+
+	switch {
+	case len(declaring) == 1:
+		return declaring[0], nil
+	case len(declaring) > 1:
+		return nil, fmt.Errorf("%w (%s %s is claimed by several documents, depending on the host or headers)", ErrNoDocument, strings.ToUpper(method), path)
+	case len(matches) > 0:
+		return matches[0], nil
+	}
+
+	return nil, fmt.Errorf("%w (%s %s)", ErrNoDocument, strings.ToUpper(method), path)
+}
+
+func (r Route) matchesPath(path string) bool {
+	return r.PathPrefix == "" || path == r.PathPrefix || strings.HasPrefix(path, r.PathPrefix+"/")
+}
+
+// document returns the route document, removing the path prefix when
+// configured.
+func (r Route) document() Document {
+	if !r.StripPrefix || r.PathPrefix == "" {
+		return r.Document
+	}
+
+	return &prefixed{r.Document, r.PathPrefix}
+}
+
+// prefixed is a Document served under a path prefix it does not declare.
+type prefixed struct {
+	Document
+	prefix string
+}
+
+func (p *prefixed) strip(path string) string {
+	path = strings.TrimPrefix(path, p.prefix)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return path
+}
+
+// Host retrieves the host of the document, empty when it does not declare a
+// server.
+func (p *prefixed) Host() string {
+	return documentHost(p.Document)
+}
+
+// Schemes retrieves the schemes of the document.
+func (p *prefixed) Schemes() []string {
+	return documentSchemes(p.Document)
+}
+
+func (p *prefixed) RequestMediaTypes(path, method string) ([]string, error) {
+	return p.Document.RequestMediaTypes(p.strip(path), method)
+}
+
+func (p *prefixed) ResponseMediaTypes(path, method string) ([]string, error) {
+	return p.Document.ResponseMediaTypes(p.strip(path), method)
+}
+
+func (p *prefixed) RequestHeaders(path, method string) (Headers, error) {
+	return p.Document.RequestHeaders(p.strip(path), method)
+}
+
+func (p *prefixed) ResponseHeaders(path, method string, statusCode int) (Headers, error) {
+	return p.Document.ResponseHeaders(p.strip(path), method, statusCode)
+}
+
+func (p *prefixed) RequestQuery(path, method string) (Query, error) {
+	return p.Document.RequestQuery(p.strip(path), method)
+}
+
+func (p *prefixed) RequestBody(path, method string) (Body, error) {
+	return p.Document.RequestBody(p.strip(path), method)
+}
+
+func (p *prefixed) RequestBodyRequired(path, method string) (bool, error) {
+	return requestBodyRequired(p.Document, p.strip(path), method)
+}
+
+func (p *prefixed) ResponseBody(path, method string, statusCode int) (Body, error) {
+	return p.Document.ResponseBody(p.strip(path), method, statusCode)
+}
+
+// Operation retrieves the operation, with the path prefix in its path.
+func (p *prefixed) Operation(path, method string) (*Operation, error) {
+	op, err := documentOperation(p.Document, p.strip(path), method)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.withPrefix(op), nil
+}
+
+// Operations retrieves the operations, with the path prefix in their paths.
+func (p *prefixed) Operations() []*Operation {
+	ops := []*Operation{}
+	docOps, _ := documentOperations(p.Document)
+
+	for _, op := range docOps {
+		ops = append(ops, p.withPrefix(op))
+	}
+
+	return ops
+}
+
+func (p *prefixed) withPrefix(op *Operation) *Operation {
+	o := *op
+	o.Path = p.prefix + o.Path
+
+	return &o
+}
@@ -0,0 +1,192 @@
+package assert
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestCompositeResolve(t *testing.T) {
+	type tt struct {
+		method   string
+		url      string
+		header   http.Header
+		expected func(docs, xml Document) *Operation
+		err      string
+	}
+
+	operation := func(doc Document, path, method string) *Operation {
+		op, _ := doc.(OperationDescriber).Operation(path, method)
+		return op
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("path prefix", tt{
+		method: http.MethodGet,
+		url:    "/api/pets/1",
+		expected: func(docs, _ Document) *Operation {
+			return operation(docs, "/api/pets/1", http.MethodGet)
+		},
+	})
+
+	tests.Add("stripped prefix", tt{
+		method: http.MethodGet,
+		url:    "/legacy/api/pets/1",
+		expected: func(docs, _ Document) *Operation {
+			op := operation(docs, "/api/pets/1", http.MethodGet)
+			op.Path = "/legacy" + op.Path
+
+			return op
+		},
+	})
+
+	tests.Add("host", tt{
+		method: http.MethodGet,
+		url:    "http://xml.example.com:8080/api/pets",
+		expected: func(_, xml Document) *Operation {
+			return operation(xml, "/api/pets", http.MethodGet)
+		},
+	})
+
+	tests.Add("header", tt{
+		method: http.MethodGet,
+		url:    "/api/pets",
+		header: http.Header{"Api-Version": {"2"}},
+		expected: func(_, xml Document) *Operation {
+			return operation(xml, "/api/pets", http.MethodGet)
+		},
+	})
+
+	tests.Add("unclaimed", tt{
+		method: http.MethodGet,
+		url:    "/unknown",
+		err:    "no document claims the request (GET /unknown)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		docs, _ := LoadFromURI("./fixtures/docs.json")
+		xml, _ := LoadFromURI("./fixtures/xml.json")
+
+		composite := NewComposite(
+			Route{Host: "xml.example.com", Document: xml},
+			Route{Header: "Api-Version", Value: "2", Document: xml},
+			Route{PathPrefix: "/legacy/", StripPrefix: true, Document: docs},
+			Route{PathPrefix: "/api", Document: docs},
+		)
+
+		req, _ := http.NewRequest(tt.method, tt.url, nil)
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+
+		op, err := New(composite).Operation(req)
+
+		testy.Error(t, tt.err, err)
+
+		if d := testy.DiffInterface(tt.expected(docs, xml), op); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestCompositeRequest(t *testing.T) {
+	type tt struct {
+		url string
+		err string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("valid", tt{
+		url: "/legacy/api/pets?limit=1",
+	})
+
+	tests.Add("invalid", tt{
+		url: "/legacy/api/pets",
+		err: "failed asserting that '{}' is a valid request query (limit is required)",
+	})
+
+	tests.Add("unclaimed", tt{
+		url: "/unknown",
+		err: "no document claims the request (GET /unknown)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		docs, _ := LoadFromURI("./fixtures/docs.json")
+		composite := NewComposite(Route{PathPrefix: "/legacy", StripPrefix: true, Document: docs})
+
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+
+		err := New(composite).Request(req)
+
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestCompositeDocument(t *testing.T) {
+	docs, _ := LoadFromURI("./fixtures/docs.json")
+	xml, _ := LoadFromURI("./fixtures/xml.json")
+
+	composite := NewComposite(
+		Route{PathPrefix: "/v2", StripPrefix: true, Document: xml},
+		Route{PathPrefix: "/api", Document: docs},
+	)
+
+	types, err := composite.ResponseMediaTypes("/v2/api/pets", http.MethodGet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := xml.ResponseMediaTypes("/api/pets", http.MethodGet)
+
+	if d := testy.DiffInterface(expected, types); d != nil {
+		t.Error(d)
+	}
+
+	if _, err := composite.RequestQuery("/v3/pets", http.MethodGet); err == nil {
+		t.Error("expected an unclaimed path error")
+	}
+
+	if got, want := len(composite.Operations()), len(docs.(OperationDescriber).Operations())+len(xml.(OperationDescriber).Operations()); got != want {
+		t.Errorf("want %d operations, got %d", want, got)
+	}
+}
+
+func TestCompositeHostRoutes(t *testing.T) {
+	docs, _ := LoadFromURI("./fixtures/docs.json")
+	xml, _ := LoadFromURI("./fixtures/xml.json")
+
+	composite := NewComposite(
+		Route{Host: "xml.example.com", Document: xml},
+		Route{Host: "docs.example.com", Document: docs},
+	)
+
+	assertions := New(composite)
+
+	if err := assertions.RequestMediaTypeFor("findFood", "application/json"); err != nil {
+		t.Errorf("expected the operation document to be used, got %v", err)
+	}
+
+	err := assertions.RequestBodyFor("updatePet", strings.NewReader(`{"name": 1}`))
+	testy.Error(t, `failed asserting that '{"name": 1}' is a valid request body (id is required, Invalid type. Expected: string, given: integer, id is required, Must validate all the schemas (allOf))`, err)
+
+	types, err := composite.RequestMediaTypes("/api/food", http.MethodGet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := testy.DiffInterface([]string{"application/json"}, types); d != nil {
+		t.Error(d)
+	}
+
+	_, err = composite.RequestMediaTypes("/api/pets", http.MethodGet)
+	testy.Error(t, "no document claims the request (GET /api/pets is claimed by several documents, depending on the host or headers)", err)
+
+	if !errors.Is(err, ErrNoDocument) {
+		t.Errorf("expected ErrNoDocument, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package assert
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+type contextKey struct{}
+
+// cancelReader cancels the context once read.
+type cancelReader struct {
+	cancel context.CancelFunc
+	data   *strings.Reader
+}
+
+func (c *cancelReader) Read(p []byte) (int, error) {
+	c.cancel()
+	return c.data.Read(p[:1])
+}
+
+func TestAssertionsContextCanceled(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+	assertions := New(doc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pets", nil)
+
+	if err := assertions.RequestContext(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAssertionsContextCanceledWhileReading(t *testing.T) {
+	type tt struct {
+		opts []Option
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("buffered", tt{})
+	tests.Add("streaming", tt{opts: []Option{WithStreaming()}})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, tt.opts...)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		body := &cancelReader{cancel, strings.NewReader(`[{"id": 1, "name": "doggo"}]`)}
+
+		err := assertions.ResponseBodyContext(ctx, body, "/api/pets", http.MethodGet, http.StatusOK)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestAssertionsContextHooks(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+
+	got := []interface{}{}
+
+	assertions := New(
+		doc,
+		WithContextDecoder("text/plain", func(ctx context.Context, data []byte, _ Body) (interface{}, error) {
+			got = append(got, ctx.Value(contextKey{}))
+			return data, nil
+		}),
+		WithReporter(func(err error) {
+			var f *Finding
+
+			if errors.As(err, &f) {
+				got = append(got, f.Context().Value(contextKey{}))
+			}
+		}),
+	)
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "trace")
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/pets/1", nil)
+
+	if err := assertions.RequestContext(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	err := assertions.RequestBodyWithMediaTypeContext(ctx, strings.NewReader(`{"name": "doggo"}`), "text/plain", "/api/food", http.MethodPost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := testy.DiffInterface([]interface{}{"trace", "trace"}, got); d != nil {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,114 @@
+package assert
+
+import (
+	"context"
+	"mime"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ErrBodyEmpty returns an error when a binary body has no content.
+const ErrBodyEmpty = err("body is empty")
+
+// Decoder decodes a body of a given media type into a json compatible value,
+// that is validated against the schema. Returning a nil value skips the
+// schema validation, while a []byte value is validated as raw json.
+type Decoder func(data []byte, schema Body) (interface{}, error)
+
+// ContextDecoder is a Decoder receiving the context of the assertion.
+type ContextDecoder func(ctx context.Context, data []byte, schema Body) (interface{}, error)
+
+// withContext adapts the decoder to a ContextDecoder.
+func (d Decoder) withContext() ContextDecoder {
+	return func(_ context.Context, data []byte, schema Body) (interface{}, error) {
+		return d(data, schema)
+	}
+}
+
+// defaultDecoders are the decoders registered by default. The keys are full
+// media types, "type/*" wildcards or "+suffix" structured syntax suffixes.
+var defaultDecoders = map[string]Decoder{
+	"application/json":         DecodeJSON,
+	"+json":                    DecodeJSON,
+	"application/xml":          DecodeXML,
+	"text/xml":                 DecodeXML,
+	"+xml":                     DecodeXML,
+	"text/*":                   DecodeText,
+	"application/octet-stream": DecodeBinary,
+	"application/pdf":          DecodeBinary,
+	"application/zip":          DecodeBinary,
+	"audio/*":                  DecodeBinary,
+	"image/*":                  DecodeBinary,
+	"video/*":                  DecodeBinary,
+}
+
+// DecodeJSON hands the raw json body to the validator.
+func DecodeJSON(data []byte, _ Body) (interface{}, error) {
+	return data, nil
+}
+
+// DecodeXML decodes a xml body following the xml hints of the schema.
+func DecodeXML(data []byte, schema Body) (interface{}, error) {
+	return decodeXML(data, schema)
+}
+
+// DecodeText decodes a text body as a json string.
+func DecodeText(data []byte, _ Body) (interface{}, error) {
+	return string(data), nil
+}
+
+// DecodeBinary only checks the presence of a binary body.
+func DecodeBinary(data []byte, _ Body) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, ErrBodyEmpty
+	}
+
+	return nil, nil
+}
+
+// decoder returns the decoder for the media type. Bodies described by a file
+// schema are always binary, while unknown media types are decoded as json.
+func (a *Assertions) decoder(mediaType string, schema Body) ContextDecoder {
+	if s, ok := schema.(*spec.Schema); ok && s != nil && s.Type.Contains("file") {
+		return Decoder(DecodeBinary).withContext()
+	}
+
+	mt, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return Decoder(DecodeJSON).withContext()
+	}
+
+	if d, ok := a.decoders[mt]; ok {
+		return d
+	}
+
+	if i := strings.LastIndex(mt, "+"); i >= 0 {
+		if d, ok := a.decoders[mt[i:]]; ok {
+			return d
+		}
+	}
+
+	if i := strings.Index(mt, "/"); i >= 0 {
+		if d, ok := a.decoders[mt[:i]+"/*"]; ok {
+			return d
+		}
+	}
+
+	return Decoder(DecodeJSON).withContext()
+}
+
+// isJSON reports whether the media type is a json media type. An empty media
+// type is handled as json.
+func isJSON(mediaType string) bool {
+	if mediaType == "" {
+		return true
+	}
+
+	mt, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
@@ -0,0 +1,69 @@
+package assert
+
+import (
+	"context"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestDecoder(t *testing.T) {
+	type tt struct {
+		mediaType string
+		data      string
+		expected  interface{}
+		err       string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("empty media type", tt{
+		data:     `{}`,
+		expected: []byte(`{}`),
+	})
+
+	tests.Add("json", tt{
+		mediaType: "application/json; charset=utf-8",
+		data:      `{}`,
+		expected:  []byte(`{}`),
+	})
+
+	tests.Add("unknown", tt{
+		mediaType: "application/x-unknown",
+		data:      `{}`,
+		expected:  []byte(`{}`),
+	})
+
+	tests.Add("xml suffix", tt{
+		mediaType: "application/atom+xml",
+		data:      `<feed>title</feed>`,
+		expected:  "title",
+	})
+
+	tests.Add("text wildcard", tt{
+		mediaType: "text/csv",
+		data:      `a,b`,
+		expected:  "a,b",
+	})
+
+	tests.Add("binary", tt{
+		mediaType: "video/mp4",
+		data:      "data",
+	})
+
+	tests.Add("empty binary", tt{
+		mediaType: "application/pdf",
+		err:       "body is empty",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		a := New(nil)
+
+		got, err := a.decoder(tt.mediaType, nil)(context.Background(), []byte(tt.data), nil)
+		testy.Error(t, tt.err, err)
+
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
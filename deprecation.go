@@ -0,0 +1,98 @@
+package assert
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Deprecation is a non fatal finding reporting the usage of a deprecated
+// operation or parameter.
+type Deprecation struct {
+	// Operation is the deprecated operation, or the operation of the
+	// deprecated parameter.
+	Operation *Operation
+
+	// Parameter is the deprecated parameter, nil when the operation is
+	// deprecated.
+	Parameter *Parameter
+}
+
+func (d *Deprecation) Error() string {
+	op := d.Operation.String()
+
+	if d.Parameter != nil {
+		return fmt.Sprintf("%s parameter '%s' of operation '%s' is deprecated", d.Parameter.In, d.Parameter.Name, op)
+	}
+
+	if d.Operation.Sunset != "" {
+		return fmt.Sprintf("operation '%s' is deprecated (sunset %s)", op, d.Operation.Sunset)
+	}
+
+	return fmt.Sprintf("operation '%s' is deprecated", op)
+}
+
+// Operation retrieves the document operation matching the request.
+func (a *Assertions) Operation(req *http.Request) (*Operation, error) {
+	r, err := a.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return documentOperation(r.doc, a.lookupPath(req.URL), req.Method)
+}
+
+// reportDeprecations reports the usage of the deprecated operation and of the
+// deprecated parameters sent in the request, failing only when deprecations
+// are configured as errors. Requests matching no operation, with a nil
+// operation, report nothing.
+func (a *Assertions) reportDeprecations(ctx context.Context, req *http.Request, op *Operation, body []byte) error {
+	if op == nil {
+		return nil
+	}
+
+	deprecations := []*Deprecation{}
+
+	if op.Deprecated {
+		deprecations = append(deprecations, &Deprecation{Operation: op})
+	}
+
+	for i := range op.Parameters {
+		param := &op.Parameters[i]
+
+		if param.Deprecated && parameterSent(req, param, body) {
+			deprecations = append(deprecations, &Deprecation{Operation: op, Parameter: param})
+		}
+	}
+
+	for _, d := range deprecations {
+		if err := a.finding(ctx, CheckDeprecation, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parameterSent reports whether the request sends the parameter.
+func parameterSent(req *http.Request, param *Parameter, body []byte) bool {
+	switch param.In {
+	case "query":
+		for k := range req.URL.Query() {
+			if strings.EqualFold(k, param.Name) {
+				return true
+			}
+		}
+	case "header":
+		return req.Header.Get(param.Name) != ""
+	case "formData":
+		return req.Form != nil && req.Form.Get(param.Name) != ""
+	case "body":
+		return len(body) > 0
+	case "path":
+		return true
+	}
+
+	return false
+}
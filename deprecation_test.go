@@ -0,0 +1,54 @@
+package assert
+
+import (
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestAssertionsDeprecations(t *testing.T) {
+	type tt struct {
+		path     string
+		expected []string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("not deprecated", tt{
+		path:     "/api/pets?limit=1",
+		expected: []string{},
+	})
+
+	tests.Add("deprecated operation", tt{
+		path: "/api/pets/1",
+		expected: []string{
+			"operation 'GET /api/pets/{id} (findPetById)' is deprecated (sunset 2030-01-01)",
+		},
+	})
+
+	tests.Add("deprecated parameter", tt{
+		path: "/api/pets?limit=1&tags=dog",
+		expected: []string{
+			"query parameter 'tags' of operation 'GET /api/pets (findPets)' is deprecated",
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		got := []string{}
+
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, WithReporter(func(err error) {
+			got = append(got, err.Error())
+		}))
+
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		if err := assertions.Request(req); err != nil {
+			t.Fatal(err)
+		}
+
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
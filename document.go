@@ -1,5 +1,11 @@
 package assert
 
+import (
+	"fmt"
+
+	"github.com/go-openapi/spec"
+)
+
 // Headers is a list of headers in json schema format.
 type Headers map[string]interface{}
 
@@ -19,6 +25,129 @@ type Body interface{}
 // Required is a list of required parameters.
 type Required []string
 
+// Operation describes a document operation.
+type Operation struct {
+	// ID is the operationId, empty when not declared.
+	ID string
+
+	// Method is the upper case http method.
+	Method string
+
+	// Path is the path template, including the document base path.
+	Path string
+
+	Tags        []string
+	Summary     string
+	Description string
+	Deprecated  bool
+
+	// Sunset is the date the operation stops working, from the x-sunset
+	// vendor extension.
+	Sunset string
+
+	// Consumes and Produces are the media types, including the document
+	// defaults.
+	Consumes []string
+	Produces []string
+
+	// Parameters are the path and operation parameters, with the operation
+	// ones overriding the path ones.
+	Parameters []Parameter
+
+	// Responses are the declared responses, sorted by status code.
+	Responses []Response
+
+	// Security lists the alternative security requirements, each one holding
+	// the schemes required together. Empty when the operation is not secured.
+	Security [][]SecurityScheme
+
+	// document is the member document declaring the operation, for the
+	// operations of a Composite.
+	document Document
+}
+
+// from returns a copy of the operation declared by the document.
+func (o *Operation) from(doc Document) *Operation {
+	op := *o
+	op.document = doc
+
+	return &op
+}
+
+// String returns the method and path of the operation, followed by its
+// operationId when declared.
+func (o *Operation) String() string {
+	if o.ID == "" {
+		return fmt.Sprintf("%s %s", o.Method, o.Path)
+	}
+
+	return fmt.Sprintf("%s %s (%s)", o.Method, o.Path, o.ID)
+}
+
+// StatusCodes returns the declared response status codes, without the
+// default response.
+func (o *Operation) StatusCodes() []int {
+	codes := []int{}
+
+	for _, res := range o.Responses {
+		if res.StatusCode != 0 {
+			codes = append(codes, res.StatusCode)
+		}
+	}
+
+	return codes
+}
+
+// SecurityScheme describes a security scheme required by an operation.
+type SecurityScheme struct {
+	// Name is the name of the scheme in the security definitions.
+	Name string
+
+	// Type is the scheme type: basic, apiKey or oauth2.
+	Type string
+
+	// In is the location of the api key, header or query.
+	In string
+
+	// ParamName is the name of the header or query parameter holding the
+	// api key.
+	ParamName string
+
+	// Scopes are the required oauth2 scopes.
+	Scopes []string
+}
+
+// Parameter describes an operation parameter.
+type Parameter struct {
+	Name             string
+	In               string
+	Description      string
+	Required         bool
+	CollectionFormat string
+
+	// Deprecated is set by the x-deprecated vendor extension, as Swagger 2.0
+	// parameters have no deprecated field.
+	Deprecated bool
+
+	// Schema is the body schema, or the json schema equivalent of the other
+	// parameters type and validations.
+	Schema *spec.Schema
+}
+
+// Response describes an operation response.
+type Response struct {
+	// StatusCode is zero for the default response.
+	StatusCode  int
+	Description string
+	Schema      *spec.Schema
+	Headers     map[string]spec.Header
+	Examples    map[string]interface{}
+}
+
+// ErrOperationsUnsupported returns an error when the document does not
+// describe its operations.
+const ErrOperationsUnsupported = err("the document does not describe its operations")
+
 // Document that defines the contract for reading OpenAPI documents.
 type Document interface {
 	// RequestMediaTypes retrives a list of request media types allowed.
@@ -42,3 +171,84 @@ type Document interface {
 	// ResponseBody retrieves the response body.
 	ResponseBody(path, method string, statusCode int) (Body, error)
 }
+
+// BodyRequirer is implemented by the documents telling whether the request
+// body is required. The request body of other documents is optional.
+type BodyRequirer interface {
+	// RequestBodyRequired retrieves whether the request body is required.
+	RequestBodyRequired(path, method string) (bool, error)
+}
+
+// requestBodyRequired retrieves whether the document requires the request
+// body, false when the document does not tell.
+func requestBodyRequired(doc Document, path, method string) (bool, error) {
+	r, ok := doc.(BodyRequirer)
+	if !ok {
+		return false, nil
+	}
+
+	return r.RequestBodyRequired(path, method)
+}
+
+// ServerDescriber is implemented by the documents declaring the server of the
+// API. The server of other documents is not validated.
+type ServerDescriber interface {
+	// Host retrieves the host serving the API, empty when not declared.
+	Host() string
+
+	// Schemes retrieves the transfer protocols of the API.
+	Schemes() []string
+}
+
+// documentHost retrieves the host of the document, empty when it does not
+// declare a server.
+func documentHost(doc Document) string {
+	if s, ok := doc.(ServerDescriber); ok {
+		return s.Host()
+	}
+
+	return ""
+}
+
+// documentSchemes retrieves the schemes of the document, nil when it does
+// not declare a server.
+func documentSchemes(doc Document) []string {
+	if s, ok := doc.(ServerDescriber); ok {
+		return s.Schemes()
+	}
+
+	return nil
+}
+
+// OperationDescriber is implemented by the documents describing their
+// operations, needed to assert by operationId and to report deprecations.
+type OperationDescriber interface {
+	// Operation retrieves the operation of the path and method.
+	Operation(path, method string) (*Operation, error)
+
+	// Operations retrieves all the document operations, sorted by path and
+	// method.
+	Operations() []*Operation
+}
+
+// documentOperation retrieves the operation of the path and method, failing
+// when the document does not describe its operations.
+func documentOperation(doc Document, path, method string) (*Operation, error) {
+	d, ok := doc.(OperationDescriber)
+	if !ok {
+		return nil, ErrOperationsUnsupported
+	}
+
+	return d.Operation(path, method)
+}
+
+// documentOperations retrieves the operations of the document, failing when
+// the document does not describe its operations.
+func documentOperations(doc Document) ([]*Operation, error) {
+	d, ok := doc.(OperationDescriber)
+	if !ok {
+		return nil, ErrOperationsUnsupported
+	}
+
+	return d.Operations(), nil
+}
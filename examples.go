@@ -0,0 +1,233 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// ErrExamplesUnsupported returns an error when the document does not expose
+// its examples, like a Composite or a document loaded without WithExamples.
+const ErrExamplesUnsupported = err("the document examples cannot be validated")
+
+// example is an example value with the schema it must match.
+type example struct {
+	pointer string
+	schema  *spec.Schema
+	value   interface{}
+}
+
+// ValidateExamples validates the examples of the definitions, parameters,
+// responses and headers against their schemas, as the Assertions validate
+// the requests, failing with a *ValidationError listing every mismatch with
+// the json pointer of the invalid value. The document must be loaded
+// WithExamples.
+func ValidateExamples(doc Document) error {
+	if r, ok := doc.(*Reloadable); ok {
+		doc = r.Current()
+	}
+
+	s, ok := doc.(*swagger)
+	if !ok || s.orig == nil {
+		return ErrExamplesUnsupported
+	}
+
+	a := New(doc)
+	issues := []SpecIssue{}
+
+	for _, ex := range s.examples() {
+		result, err := a.validate(ex.schema, ex.value)
+		if err != nil {
+			issues = append(issues, SpecIssue{
+				Pointer: ex.pointer,
+				Message: fmt.Sprintf("invalid schema: %s", err),
+			})
+
+			continue
+		}
+
+		for _, issue := range resultIssues("example", ex.pointer, result) {
+			issues = append(issues, SpecIssue{Pointer: issue.Pointer, Message: issue.Message})
+		}
+	}
+
+	return newValidationError(issues, s.raw)
+}
+
+// examples walks the spec before expansion, so each example is found once
+// at its location in the document, and pairs it with the expanded schema.
+func (s *swagger) examples() []example {
+	w := &exampleWalker{}
+
+	if s.orig == nil {
+		return w.examples
+	}
+
+	for _, name := range sortedKeys(s.orig.Definitions) {
+		orig, exp := s.orig.Definitions[name], s.spec.Definitions[name]
+		w.schema(&orig, &exp, "/definitions/"+escapePointer(name))
+	}
+
+	for _, name := range sortedKeys(s.orig.Parameters) {
+		w.parameter(s.orig.Parameters[name], s.spec.Parameters[name], "/parameters/"+escapePointer(name))
+	}
+
+	for _, name := range sortedKeys(s.orig.Responses) {
+		orig, exp := s.orig.Responses[name], s.spec.Responses[name]
+		w.response(&orig, &exp, "/responses/"+escapePointer(name))
+	}
+
+	if s.orig.Paths == nil || s.spec.Paths == nil {
+		return w.examples
+	}
+
+	for _, path := range sortedKeys(s.orig.Paths.Paths) {
+		orig, exp := s.orig.Paths.Paths[path], s.spec.Paths.Paths[path]
+		pointer := "/paths/" + escapePointer(path)
+
+		w.parameters(orig.Parameters, exp.Parameters, pointer)
+
+		for _, method := range methods {
+			origOp, expOp := operationOf(orig, method), operationOf(exp, method)
+			if origOp == nil || expOp == nil {
+				continue
+			}
+
+			opPointer := pointer + "/" + strings.ToLower(method)
+
+			w.parameters(origOp.Parameters, expOp.Parameters, opPointer)
+			w.responses(origOp.Responses, expOp.Responses, opPointer+"/responses")
+		}
+	}
+
+	return w.examples
+}
+
+// exampleWalker collects the examples walking the spec before and after
+// expansion in parallel, not following the references of the former, as
+// the referenced definitions are walked on their own.
+type exampleWalker struct {
+	examples []example
+}
+
+func (w *exampleWalker) add(pointer string, schema *spec.Schema, value interface{}) {
+	w.examples = append(w.examples, example{pointer, schema, value})
+}
+
+func (w *exampleWalker) schema(orig, exp *spec.Schema, pointer string) {
+	if orig == nil || exp == nil || orig.Ref.String() != "" {
+		return
+	}
+
+	if orig.Example != nil {
+		w.add(pointer+"/example", exp, orig.Example)
+	}
+
+	for _, name := range sortedKeys(orig.Properties) {
+		origProp, expProp := orig.Properties[name], exp.Properties[name]
+		w.schema(&origProp, &expProp, pointer+"/properties/"+escapePointer(name))
+	}
+
+	if orig.Items != nil && exp.Items != nil {
+		w.schema(orig.Items.Schema, exp.Items.Schema, pointer+"/items")
+
+		for i := range orig.Items.Schemas {
+			if i < len(exp.Items.Schemas) {
+				w.schema(&orig.Items.Schemas[i], &exp.Items.Schemas[i], pointer+"/items/"+strconv.Itoa(i))
+			}
+		}
+	}
+
+	for i := range orig.AllOf {
+		if i < len(exp.AllOf) {
+			w.schema(&orig.AllOf[i], &exp.AllOf[i], pointer+"/allOf/"+strconv.Itoa(i))
+		}
+	}
+
+	if orig.AdditionalProperties != nil && exp.AdditionalProperties != nil {
+		w.schema(orig.AdditionalProperties.Schema, exp.AdditionalProperties.Schema, pointer+"/additionalProperties")
+	}
+}
+
+func (w *exampleWalker) parameters(orig, exp []spec.Parameter, pointer string) {
+	for i := range orig {
+		if i < len(exp) {
+			w.parameter(orig[i], exp[i], fmt.Sprintf("%s/parameters/%d", pointer, i))
+		}
+	}
+}
+
+func (w *exampleWalker) parameter(orig, exp spec.Parameter, pointer string) {
+	if orig.Ref.String() != "" {
+		return
+	}
+
+	if orig.In == "body" {
+		w.schema(orig.Schema, exp.Schema, pointer+"/schema")
+		return
+	}
+
+	if orig.Example != nil {
+		w.add(pointer+"/example", parameterSchema(exp), orig.Example)
+	}
+}
+
+func (w *exampleWalker) responses(orig, exp *spec.Responses, pointer string) {
+	if orig == nil || exp == nil {
+		return
+	}
+
+	w.response(orig.Default, exp.Default, pointer+"/default")
+
+	codes := []int{}
+	for code := range orig.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		origRes, expRes := orig.StatusCodeResponses[code], exp.StatusCodeResponses[code]
+		w.response(&origRes, &expRes, fmt.Sprintf("%s/%d", pointer, code))
+	}
+}
+
+func (w *exampleWalker) response(orig, exp *spec.Response, pointer string) {
+	if orig == nil || exp == nil || orig.Ref.String() != "" {
+		return
+	}
+
+	w.schema(orig.Schema, exp.Schema, pointer+"/schema")
+
+	if exp.Schema != nil {
+		for _, mediaType := range sortedKeys(orig.Examples) {
+			if strings.HasSuffix(mediaType, "json") {
+				w.add(pointer+"/examples/"+escapePointer(mediaType), exp.Schema, orig.Examples[mediaType])
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(orig.Headers) {
+		if header := orig.Headers[name]; header.Example != nil {
+			schema := simpleSchema(exp.Headers[name].SimpleSchema, exp.Headers[name].CommonValidations)
+			w.add(pointer+"/headers/"+escapePointer(name)+"/example", schema, header.Example)
+		}
+	}
+}
+
+// sortedKeys returns the keys of the map with string keys in order.
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
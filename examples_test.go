@@ -0,0 +1,82 @@
+package assert
+
+import (
+	"errors"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestValidateExamples(t *testing.T) {
+	type tt struct {
+		doc    func() (Document, error)
+		issues []SpecIssue
+		err    string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("valid examples", tt{
+		doc: func() (Document, error) {
+			return LoadFromURI("./fixtures/docs.json", WithExamples())
+		},
+	})
+
+	tests.Add("invalid examples", tt{
+		doc: func() (Document, error) {
+			return LoadFromURI("./fixtures/examples.json", WithExamples())
+		},
+		issues: []SpecIssue{
+			{Pointer: "/paths/~1pets~1{id}/parameters/0/example", Line: 18, Message: "Invalid type. Expected: integer, given: string"},
+			{Pointer: "/paths/~1pets~1{id}/get/responses/200/headers/X-Rate-Limit/example", Line: 37, Message: "Must be greater than or equal to 1"},
+			{Pointer: "/paths/~1pets~1{id}/get/responses/default/examples/application~1problem+json/code", Line: 54, Message: "code is required"},
+			{Pointer: "/parameters/limit/example", Line: 86, Message: "Must be less than or equal to 100"},
+			{Pointer: "/definitions/Pet/properties/tag/example", Line: 106, Message: "Invalid type. Expected: string, given: integer"},
+			{Pointer: "/definitions/Pet/example/name", Line: 109, Message: "name is required"},
+			{Pointer: "/definitions/Pet/example/id", Line: 110, Message: "Invalid type. Expected: integer, given: string"},
+		},
+	})
+
+	tests.Add("reloadable document", tt{
+		doc: func() (Document, error) {
+			return NewReloadable("./fixtures/docs.json", WithExamples())
+		},
+	})
+
+	tests.Add("loaded without examples", tt{
+		doc: func() (Document, error) {
+			return LoadFromURI("./fixtures/docs.json")
+		},
+		err: "the document examples cannot be validated",
+	})
+
+	tests.Add("composite document", tt{
+		doc: func() (Document, error) {
+			doc, err := LoadFromURI("./fixtures/docs.json")
+			return NewComposite(Route{Document: doc}), err
+		},
+		err: "the document examples cannot be validated",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, err := tt.doc()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ValidateExamples(doc)
+		if tt.issues == nil {
+			testy.Error(t, tt.err, err)
+			return
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if d := testy.DiffInterface(tt.issues, verr.Issues); d != nil {
+			t.Error(d)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package assert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Severity is the level of a finding.
+type Severity int
+
+const (
+	// SeverityError fails the assertion.
+	SeverityError Severity = iota
+
+	// SeverityWarning is handed to the reporter without failing the assertion.
+	SeverityWarning
+
+	// SeverityInfo is handed to the reporter without failing the assertion.
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+
+	return "unknown"
+}
+
+// Check identifies the check producing a finding.
+type Check string
+
+// Checks performed by the assertions.
+const (
+	CheckServer                   Check = "server"
+	CheckRequestMediaType         Check = "request-media-type"
+	CheckResponseMediaType        Check = "response-media-type"
+	CheckRequestHeaders           Check = "request-headers"
+	CheckResponseHeaders          Check = "response-headers"
+	CheckSecurity                 Check = "security"
+	CheckRequestAccept            Check = "request-accept"
+	CheckRequestQuery             Check = "request-query"
+	CheckRequestBody              Check = "request-body"
+	CheckResponseBody             Check = "response-body"
+	CheckRequiredBody             Check = "required-body"
+	CheckUndeclaredQuery          Check = "undeclared-query"
+	CheckUndeclaredRequestHeader  Check = "undeclared-request-header"
+	CheckUndeclaredResponseHeader Check = "undeclared-response-header"
+	CheckUndeclaredProperty       Check = "undeclared-property"
+	CheckDeprecation              Check = "deprecation"
+)
+
+// defaultSeverities are the severities of the checks not reported as errors
+// by default.
+var defaultSeverities = map[Check]Severity{
+	CheckDeprecation: SeverityWarning,
+}
+
+// Finding is a violation reported by a check.
+type Finding struct {
+	// Check is the check producing the finding.
+	Check Check
+
+	// Severity is the severity configured for the check.
+	Severity Severity
+
+	// Err is the violation.
+	Err error
+
+	// Issues are the problems found by the check, when known.
+	Issues []Issue
+
+	ctx context.Context
+}
+
+// Context returns the context of the assertion producing the finding.
+func (f *Finding) Context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+
+	return f.ctx
+}
+
+func (f *Finding) Error() string {
+	return f.Err.Error()
+}
+
+// Unwrap returns the violation.
+func (f *Finding) Unwrap() error {
+	return f.Err
+}
+
+// severity returns the severity configured for the check.
+func (a *Assertions) severity(check Check) Severity {
+	if s, ok := a.severities[check]; ok {
+		return s
+	}
+
+	return defaultSeverities[check]
+}
+
+// finding applies the severity of the check to the violation, returning it
+// when it is an error and reporting it otherwise.
+func (a *Assertions) finding(ctx context.Context, check Check, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	f := &Finding{Check: check, Severity: a.severity(check), Err: err, ctx: ctx}
+
+	var fl *failure
+
+	if errors.As(err, &fl) {
+		f.Issues = fl.issues
+	}
+
+	if f.Severity == SeverityError {
+		return f
+	}
+
+	a.report(f)
+
+	return nil
+}
+
+// report hands a non fatal finding to the reporter.
+func (a *Assertions) report(err error) {
+	if a.reporter != nil {
+		a.reporter(err)
+	}
+}
+
+// Issue is a single problem found by a check.
+type Issue struct {
+	// Location is the part of the message holding the problem, like "url",
+	// "header", "query" or "body".
+	Location string `json:"location"`
+
+	// Pointer is the json pointer of the value in the location.
+	Pointer string `json:"pointer"`
+
+	// Keyword is the failing schema keyword.
+	Keyword string `json:"keyword"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// failure is a failed assertion along with its issues.
+type failure struct {
+	message string
+	issues  []Issue
+}
+
+func (f *failure) Error() string {
+	return f.message
+}
+
+// failIssues returns a failed assertion holding the issues.
+func failIssues(issues []Issue, format string, a ...interface{}) error {
+	return &failure{failf(format, a...).Error(), issues}
+}
+
+// issueMessages returns the messages of the issues.
+func issueMessages(issues []Issue) []string {
+	messages := []string{}
+
+	for _, issue := range issues {
+		messages = append(messages, issue.Message)
+	}
+
+	return messages
+}
+
+// keywords maps the validator error types to the schema keywords.
+var keywords = map[string]string{
+	"invalid_type":                    "type",
+	"number_any_of":                   "anyOf",
+	"number_one_of":                   "oneOf",
+	"number_all_of":                   "allOf",
+	"number_not":                      "not",
+	"missing_dependency":              "dependencies",
+	"array_no_additional_items":       "additionalItems",
+	"array_min_items":                 "minItems",
+	"array_max_items":                 "maxItems",
+	"unique":                          "uniqueItems",
+	"array_min_properties":            "minProperties",
+	"array_max_properties":            "maxProperties",
+	"additional_property_not_allowed": "additionalProperties",
+	"invalid_property_pattern":        "patternProperties",
+	"invalid_property_name":           "propertyNames",
+	"string_gte":                      "minLength",
+	"string_lte":                      "maxLength",
+	"multiple_of":                     "multipleOf",
+	"number_gte":                      "minimum",
+	"number_gt":                       "exclusiveMinimum",
+	"number_lte":                      "maximum",
+	"number_lt":                       "exclusiveMaximum",
+	"condition_then":                  "then",
+	"condition_else":                  "else",
+}
+
+// resultIssues returns the issues of the validation errors, with pointers
+// relative to the prefix.
+func resultIssues(location, prefix string, result *gojsonschema.Result) []Issue {
+	issues := []Issue{}
+
+	for _, v := range result.Errors() {
+		keyword, ok := keywords[v.Type()]
+		if !ok {
+			keyword = v.Type()
+		}
+
+		issues = append(issues, Issue{
+			Location: location,
+			Pointer:  prefix + resultPointer(v),
+			Keyword:  keyword,
+			Message:  v.Description(),
+		})
+	}
+
+	return issues
+}
+
+// resultPointer returns the json pointer of the value failing the validation,
+// pointing to the missing or additional property when there is one.
+func resultPointer(v gojsonschema.ResultError) string {
+	segments := strings.Split(v.Context().String("\x00"), "\x00")[1:]
+
+	if property, ok := v.Details()["property"]; ok {
+		switch v.Type() {
+		case "required", "additional_property_not_allowed":
+			segments = append(segments, fmt.Sprint(property))
+		}
+	}
+
+	pointer := ""
+
+	for _, segment := range segments {
+		pointer += "/" + escapePointer(segment)
+	}
+
+	return pointer
+}
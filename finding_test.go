@@ -0,0 +1,177 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestAssertionsSeverity(t *testing.T) {
+	type tt struct {
+		opts     []Option
+		method   string
+		path     string
+		body     string
+		err      string
+		reported []string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("undeclared query as warning", tt{
+		opts:   []Option{WithStrict(), WithSeverity(CheckUndeclaredQuery, SeverityWarning)},
+		method: http.MethodGet,
+		path:   "/api/pets?limit=1&page=1",
+		reported: []string{
+			`warning undeclared-query: failed asserting that '{"limit":["1"],"page":["1"]}' is a valid request query (page is not declared)`,
+		},
+	})
+
+	tests.Add("body failure remains an error", tt{
+		opts:   []Option{WithStrict(), WithSeverity(CheckUndeclaredProperty, SeverityWarning)},
+		method: http.MethodPost,
+		path:   "/api/food",
+		body:   `{"tag": "dog"}`,
+		err:    `failed asserting that '{"tag": "dog"}' is a valid request body (name is required)`,
+	})
+
+	tests.Add("body failure as info", tt{
+		opts:   []Option{WithSeverity(CheckRequestBody, SeverityInfo)},
+		method: http.MethodPost,
+		path:   "/api/food",
+		body:   `{"tag": "dog"}`,
+		reported: []string{
+			`info request-body: failed asserting that '{"tag": "dog"}' is a valid request body (name is required)`,
+		},
+	})
+
+	tests.Add("malformed body as warning", tt{
+		opts:   []Option{WithSeverity(CheckRequestBody, SeverityWarning)},
+		method: http.MethodPost,
+		path:   "/api/food",
+		body:   `{"name": "dog"`,
+		reported: []string{
+			"warning request-body: unexpected EOF",
+		},
+	})
+
+	tests.Add("malformed body is an error", tt{
+		method: http.MethodPost,
+		path:   "/api/food",
+		body:   `{"name": "dog"`,
+		err:    "unexpected EOF",
+	})
+
+	tests.Add("deprecation as error", tt{
+		opts:   []Option{WithSeverity(CheckDeprecation, SeverityError)},
+		method: http.MethodGet,
+		path:   "/api/pets/1",
+		err:    "operation 'GET /api/pets/{id} (findPetById)' is deprecated (sunset 2030-01-01)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		reported := []string{}
+
+		opts := append(tt.opts, WithReporter(func(err error) {
+			var f *Finding
+
+			if errors.As(err, &f) {
+				reported = append(reported, fmt.Sprintf("%s %s: %s", f.Severity, f.Check, f.Error()))
+			}
+		}))
+
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, opts...)
+
+		req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+
+		err := assertions.Request(req)
+
+		testy.Error(t, tt.err, err)
+
+		if tt.reported == nil {
+			tt.reported = []string{}
+		}
+
+		if d := testy.DiffInterface(tt.reported, reported); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestAssertionsUndeclaredResponseHeaders(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+	assertions := New(doc, WithStrict())
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("ETag", "1")
+	header.Set("X-Powered-By", "go")
+
+	err := assertions.ResponseHeaders(header, "/api/pets", http.MethodGet, http.StatusOK)
+
+	var f *Finding
+
+	if !errors.As(err, &f) || f.Check != CheckUndeclaredResponseHeader {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `failed asserting that '{"content-type":"application/json","etag":"1","x-powered-by":"go"}' is a valid response header (x-powered-by is not declared)`
+
+	testy.Error(t, expected, err)
+}
+
+func TestFindingIssues(t *testing.T) {
+	type tt struct {
+		opts     []Option
+		body     string
+		expected []Issue
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("buffered", tt{
+		body: `[{"id": 1, "name": "doggo"}, {"id": 2, "name": 2}]`,
+		expected: []Issue{
+			{Location: "body", Pointer: "/1/name", Keyword: "type", Message: "Invalid type. Expected: string, given: integer"},
+		},
+	})
+
+	tests.Add("streaming", tt{
+		opts: []Option{WithStreaming()},
+		body: `[{"id": 1, "name": "doggo"}, {"id": 2}]`,
+		expected: []Issue{
+			{Location: "body", Pointer: "/1/name", Keyword: "required", Message: "name is required"},
+		},
+	})
+
+	tests.Add("undeclared", tt{
+		opts: []Option{WithStrict(), WithStreaming()},
+		body: `[{"id": 1, "name": "doggo", "a/b": 1}]`,
+		expected: []Issue{
+			{Location: "body", Pointer: "/0/a~1b", Keyword: "undeclared", Message: "/a~1b is not declared"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, tt.opts...)
+
+		err := assertions.ResponseBody(strings.NewReader(tt.body), "/api/pets", http.MethodGet, http.StatusOK)
+
+		var f *Finding
+
+		if !errors.As(err, &f) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if d := testy.DiffInterface(tt.expected, f.Issues); d != nil {
+			t.Error(d)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-openapi/swag"
+)
+
+// LoadOption configures the document loading.
+type LoadOption func(*loadConfig)
+
+// Format is the serialization format of a document.
+type Format string
+
+// Document formats.
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+type loadConfig struct {
+	validate bool
+	examples bool
+	format   Format
+	offline  bool
+	docs     map[string][]byte
+	files    map[string]string
+	cache    *ReferenceCache
+}
+
+func newLoadConfig(opts []LoadOption) *loadConfig {
+	cfg := &loadConfig{}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// WithValidation validates the document on load, against the Swagger 2.0
+// schema and for semantic errors like duplicate operationIds or undeclared
+// path parameters, failing with a *ValidationError listing every issue.
+func WithValidation() LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.validate = true
+	}
+}
+
+// WithExamples keeps the document as written, before the expansion of its
+// references, so its examples can be validated by ValidateExamples.
+func WithExamples() LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.examples = true
+	}
+}
+
+// WithFormat sets the format of the document read, instead of detecting it.
+func WithFormat(format Format) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.format = format
+	}
+}
+
+// WithContentType sets the format of the document read from its media type,
+// like application/yaml or application/vnd.oai.openapi+json. Unknown media
+// types are ignored, and the format is detected.
+func WithContentType(contentType string) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.format = contentTypeFormat(contentType)
+	}
+}
+
+// contentTypeFormat returns the format of the media type, empty when unknown.
+func contentTypeFormat(contentType string) Format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return FormatJSON
+	case strings.HasSuffix(mediaType, "yaml"), mediaType == "application/vnd.oai.openapi":
+		return FormatYAML
+	}
+
+	return ""
+}
+
+// toJSON converts the document data to json. Without a format, data not
+// valid json is tried as yaml, and left to the json decoder when it looks
+// like json but is not yaml either.
+func toJSON(data []byte, format Format) ([]byte, error) {
+	if format == FormatJSON {
+		return data, nil
+	}
+
+	trimmed := bytes.TrimSpace(data)
+
+	if format == "" && json.Valid(trimmed) {
+		return trimmed, nil
+	}
+
+	doc, err := swag.BytesToYAMLDoc(trimmed)
+	if err == nil {
+		var converted json.RawMessage
+		if converted, err = swag.YAMLToJSON(doc); err == nil {
+			return converted, nil
+		}
+	}
+
+	if format == "" && (bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("["))) {
+		return trimmed, nil
+	}
+
+	return nil, err
+}
+
+// fsLoads counts the file system loads, giving each one its own uri host so
+// the documents cached while expanding do not leak between file systems.
+var fsLoads uint64
+
+// fsURI returns the uri of the named file, under a host unique to the load.
+func fsURI(name string) string {
+	u := url.URL{
+		Scheme: "fs",
+		Host:   strconv.FormatUint(atomic.AddUint64(&fsLoads, 1), 10),
+		Path:   "/" + name,
+	}
+
+	return u.String()
+}
+
+// fsReader returns a document reader of the uris returned by fsURI from the
+// file system.
+func fsReader(fsys fs.FS) docReader {
+	return func(uri string) ([]byte, error) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		if u.Scheme != "fs" {
+			return nil, fmt.Errorf("reference '%s' is outside the file system", uri)
+		}
+
+		return fs.ReadFile(fsys, strings.TrimPrefix(u.Path, "/"))
+	}
+}
@@ -2,7 +2,11 @@
 package echo
 
 import (
+	"context"
+	"errors"
+	"math/rand"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	mw "github.com/labstack/echo/v4/middleware"
@@ -17,8 +21,48 @@ type AssertConfig struct {
 
 	// OpenAPI Document
 	Document assert.Document
+
+	// MaxBodySize limits the request body size in bytes. Requests with larger
+	// bodies are rejected with status 413. Zero means no limit.
+	MaxBodySize int64
+
+	// Deprecation adds the Deprecation and Sunset response headers to the
+	// deprecated operations. Rejected requests get no deprecation headers.
+	// The deprecated calls are handed to the reporter in any case.
+	Deprecation bool
+
+	// Security asserts the request provides the credentials required by the
+	// operation, responding 401 when missing.
+	Security bool
+
+	// Accept asserts the request Accept header against the media types
+	// produced by the operation, responding 406 when none is acceptable.
+	Accept bool
+
+	// ReportOnly never blocks the requests, handing the violations to the
+	// reporter instead.
+	ReportOnly bool
+
+	// Reporter receives the violations ignored in report-only mode and the
+	// non fatal findings. Without a reporter, both are logged as warnings in
+	// the echo logger.
+	Reporter func(ctx echo.Context, err error)
+
+	// ErrorHandler responds the violations. Defaults to ProblemHandler.
+	ErrorHandler func(ctx echo.Context, err error) error
+
+	// SampleRate is the fraction of requests asserted, between 0 and 1. Zero
+	// asserts every request.
+	SampleRate float64
 }
 
+// contextKey stores the echo context in the request context, for the
+// reporter of the findings.
+type contextKey struct{}
+
+// random returns the number used to sample the requests.
+var random = rand.Float64
+
 // DefaultAssertConfig is the default Assert middleware config.
 var DefaultAssertConfig = AssertConfig{
 	Skipper: mw.DefaultSkipper,
@@ -44,23 +88,119 @@ func AssertWithConfig(cfg AssertConfig) echo.MiddlewareFunc {
 		panic("echo: assert middleware requires an openapi-assert document")
 	}
 
-	assert := assert.New(cfg.Document)
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = ProblemHandler
+	}
+
+	if cfg.Reporter == nil {
+		cfg.Reporter = logReporter
+	}
+
+	opts := []assert.Option{assert.WithReporter(findingReporter(cfg.Reporter))}
+
+	if cfg.Security {
+		opts = append(opts, assert.WithSecurityValidation())
+	}
+
+	if cfg.Accept {
+		opts = append(opts, assert.WithAcceptValidation())
+	}
+
+	if cfg.MaxBodySize > 0 {
+		opts = append(opts, assert.WithMaxBodySize(cfg.MaxBodySize))
+	}
+
+	assertions := assert.New(cfg.Document, opts...)
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
-			if cfg.Skipper(ctx) {
+			if cfg.Skipper(ctx) || !sampled(cfg.SampleRate) {
 				return next(ctx)
 			}
 
-			if err := assert.Request(ctx.Request()); err != nil {
-				return &echo.HTTPError{
-					Code:     http.StatusBadRequest,
-					Message:  err.Error(),
-					Internal: err,
+			req := ctx.Request()
+			c := context.WithValue(req.Context(), contextKey{}, ctx)
+
+			err := assertions.RequestContext(c, req)
+			if err != nil {
+				if !cfg.ReportOnly {
+					return cfg.ErrorHandler(ctx, err)
 				}
+
+				cfg.Reporter(ctx, err)
+			}
+
+			op, e := assertions.Operation(req)
+			if e != nil {
+				return next(ctx)
+			}
+
+			if err == nil {
+				store(ctx, assertions, op)
+			}
+
+			if cfg.Deprecation {
+				deprecate(ctx, op)
 			}
 
 			return next(ctx)
 		}
 	}
 }
+
+// sampled reports whether a request is asserted with the sample rate.
+func sampled(rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+
+	return random() < rate
+}
+
+// findingReporter hands the non fatal findings to the reporter, along with
+// the echo context of the request.
+func findingReporter(reporter func(echo.Context, error)) func(error) {
+	return func(err error) {
+		var f *assert.Finding
+
+		if !errors.As(err, &f) {
+			return
+		}
+
+		if ctx, ok := f.Context().Value(contextKey{}).(echo.Context); ok {
+			reporter(ctx, err)
+		}
+	}
+}
+
+// logReporter logs the violations as warnings, along with the caller.
+func logReporter(ctx echo.Context, err error) {
+	req := ctx.Request()
+	ctx.Logger().Warnf("openapi-assert: %s %s called by %s (%s): %v", req.Method, req.URL.Path, ctx.RealIP(), req.UserAgent(), err)
+}
+
+// deprecate adds the deprecation headers when the operation is deprecated.
+func deprecate(ctx echo.Context, op *assert.Operation) {
+	if !op.Deprecated {
+		return
+	}
+
+	header := ctx.Response().Header()
+	header.Set("Deprecation", "true")
+
+	if op.Sunset != "" {
+		header.Set("Sunset", sunset(op.Sunset))
+	}
+}
+
+// sunset formats the sunset date as a http date, keeping the value when it
+// cannot be parsed.
+func sunset(value string) string {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC().Format(http.TimeFormat)
+		}
+	}
+
+	return value
+}
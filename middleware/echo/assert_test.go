@@ -1,12 +1,14 @@
 package echo
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	ec "github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 	"gitlab.com/flimzy/testy"
 
 	oapi "github.com/faabiosr/openapi-assert"
@@ -23,8 +25,8 @@ func TestMiddlewareWithConfig(t *testing.T) {
 	tests := testy.NewTable()
 
 	tests.Add("with config", tt{
-		cfg: AssertConfig{Document: doc},
-		err: `code=400, message=failed asserting that '{"Content-Type":"application/json"}' is a valid request header (x-required-header is required), internal=failed asserting that '{"Content-Type":"application/json"}' is a valid request header (x-required-header is required)`,
+		cfg: AssertConfig{Document: doc, ErrorHandler: HTTPErrorHandler},
+		err: `code=400, message=failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required), internal=failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)`,
 	})
 
 	tests.Add("with skipper", tt{
@@ -79,3 +81,251 @@ func TestMiddleware(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestMiddlewareMaxBodySize(t *testing.T) {
+	reader := strings.NewReader(`{"id": 1, "name": "doggo"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/pets", reader)
+	req.Header.Add("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	c := ec.New().NewContext(req, rec)
+	doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+	err := AssertWithConfig(AssertConfig{Document: doc, MaxBodySize: 10, ErrorHandler: HTTPErrorHandler})(func(ctx ec.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})(c)
+
+	testy.Error(t, "code=413, message=body exceeds the maximum size, internal=body exceeds the maximum size", err)
+}
+
+func TestMiddlewareDeprecation(t *testing.T) {
+	type tt struct {
+		path      string
+		mediaType string
+		body      string
+		header    http.Header
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("not deprecated", tt{
+		path:   "/api/pets?limit=1",
+		header: http.Header{"Content-Type": {"text/plain; charset=UTF-8"}},
+	})
+
+	tests.Add("deprecated", tt{
+		path: "/api/pets/1",
+		header: http.Header{
+			"Content-Type": {"text/plain; charset=UTF-8"},
+			"Deprecation":  {"true"},
+			"Sunset":       {"Tue, 01 Jan 2030 00:00:00 GMT"},
+		},
+	})
+
+	tests.Add("deprecated rejected", tt{
+		path:      "/api/pets/1",
+		mediaType: "text/html",
+		body:      "<p>doggo</p>",
+		header:    http.Header{"Content-Type": {"application/problem+json"}},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		req := httptest.NewRequest(http.MethodGet, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+
+		if tt.mediaType != "" {
+			req.Header.Set("Content-Type", tt.mediaType)
+		}
+
+		c := ec.New().NewContext(req, rec)
+		doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+		err := AssertWithConfig(AssertConfig{Document: doc, Deprecation: true})(func(ctx ec.Context) error {
+			return ctx.String(http.StatusOK, "test")
+		})(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if d := testy.DiffInterface(tt.header, rec.Header()); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestMiddlewareReportOnly(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "/api/pets/1", nil)
+	req.Header.Add("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	c := ec.New().NewContext(req, rec)
+	doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+	reported := []string{}
+
+	cfg := AssertConfig{
+		Document:   doc,
+		ReportOnly: true,
+		Reporter: func(ctx ec.Context, err error) {
+			reported = append(reported, err.Error())
+		},
+	}
+
+	err := AssertWithConfig(cfg)(func(ctx ec.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)`,
+	}
+
+	if d := testy.DiffInterface(expected, reported); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestMiddlewareReporterFindings(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/pets/1", nil)
+	rec := httptest.NewRecorder()
+
+	c := ec.New().NewContext(req, rec)
+	doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+	reported := []string{}
+
+	cfg := AssertConfig{
+		Document: doc,
+		Reporter: func(ctx ec.Context, err error) {
+			reported = append(reported, ctx.Request().URL.Path+": "+err.Error())
+		},
+	}
+
+	err := AssertWithConfig(cfg)(func(ctx ec.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"/api/pets/1: operation 'GET /api/pets/{id} (findPetById)' is deprecated (sunset 2030-01-01)",
+	}
+
+	if d := testy.DiffInterface(expected, reported); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestMiddlewareDefaultReporter(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/pets/1", nil)
+	req.Header.Set("User-Agent", "petstore-client")
+	rec := httptest.NewRecorder()
+
+	logs := &bytes.Buffer{}
+
+	e := ec.New()
+	e.Logger.SetOutput(logs)
+	e.Logger.SetLevel(log.WARN)
+
+	c := e.NewContext(req, rec)
+	doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+	err := AssertWithConfig(AssertConfig{Document: doc, Deprecation: true})(func(ctx ec.Context) error {
+		return ctx.String(http.StatusOK, "test")
+	})(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "GET /api/pets/1 called by 192.0.2.1 (petstore-client): operation 'GET /api/pets/{id} (findPetById)' is deprecated (sunset 2030-01-01)"
+
+	if !strings.Contains(logs.String(), expected) {
+		t.Errorf("expected the finding to be logged, got %q", logs.String())
+	}
+
+	if n := strings.Count(logs.String(), "is deprecated"); n != 1 {
+		t.Errorf("expected the deprecation to be logged once, got %d times", n)
+	}
+}
+
+func TestMiddlewareSampleRate(t *testing.T) {
+	type tt struct {
+		rate   float64
+		random float64
+		err    string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("sampled", tt{
+		rate:   0.5,
+		random: 0.4,
+		err:    `code=400, message=failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required), internal=failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)`,
+	})
+
+	tests.Add("not sampled", tt{
+		rate:   0.5,
+		random: 0.6,
+	})
+
+	tests.Add("every request", tt{
+		random: 0.9,
+		err:    `code=400, message=failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required), internal=failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)`,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		defer func(r func() float64) { random = r }(random)
+
+		random = func() float64 { return tt.random }
+
+		req := httptest.NewRequest(http.MethodPatch, "/api/pets/1", nil)
+		req.Header.Add("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		c := ec.New().NewContext(req, rec)
+		doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+		err := AssertWithConfig(AssertConfig{Document: doc, SampleRate: tt.rate, ErrorHandler: HTTPErrorHandler})(func(ctx ec.Context) error {
+			return ctx.String(http.StatusOK, "test")
+		})(c)
+
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestMiddlewareParams(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/pets?limit=10&tags=dog,cat", nil)
+	rec := httptest.NewRecorder()
+
+	c := ec.New().NewContext(req, rec)
+	doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+	err := Assert(doc)(func(ctx ec.Context) error {
+		op, ok := OperationFrom(ctx)
+		if !ok || op.ID != "findPets" {
+			t.Errorf("unexpected operation: %v", op)
+		}
+
+		params, ok := ParamsFrom(ctx)
+		if !ok {
+			t.Fatal("missing params")
+		}
+
+		if limit, ok := params.Query.Int("limit"); !ok || limit != 10 {
+			t.Errorf("unexpected limit: %v", limit)
+		}
+
+		tags, _ := params.Query.Strings("tags")
+		if d := testy.DiffInterface([]string{"dog", "cat"}, tags); d != nil {
+			t.Error(d)
+		}
+
+		return ctx.String(http.StatusOK, "test")
+	})(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,35 @@
+package echo
+
+import (
+	"github.com/labstack/echo/v4"
+
+	assert "github.com/faabiosr/openapi-assert"
+)
+
+const (
+	// OperationKey is the echo context key of the matched operation.
+	OperationKey = "openapi-assert.operation"
+
+	// ParamsKey is the echo context key of the validated parameters.
+	ParamsKey = "openapi-assert.params"
+)
+
+// OperationFrom returns the operation matched by the middleware.
+func OperationFrom(ctx echo.Context) (*assert.Operation, bool) {
+	op, ok := ctx.Get(OperationKey).(*assert.Operation)
+	return op, ok
+}
+
+// ParamsFrom returns the parameters validated by the middleware, coerced to
+// the types declared in the document.
+func ParamsFrom(ctx echo.Context) (*assert.Params, bool) {
+	params, ok := ctx.Get(ParamsKey).(*assert.Params)
+	return params, ok
+}
+
+// store keeps the matched operation and the validated parameters in the echo
+// context.
+func store(ctx echo.Context, assertions *assert.Assertions, op *assert.Operation) {
+	ctx.Set(OperationKey, op)
+	ctx.Set(ParamsKey, assertions.Params(ctx.Request(), op))
+}
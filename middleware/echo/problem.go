@@ -0,0 +1,94 @@
+package echo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	assert "github.com/faabiosr/openapi-assert"
+)
+
+// ProblemMediaType is the media type of the problem details responses.
+const ProblemMediaType = "application/problem+json"
+
+// Problem is a RFC 7807 problem details response describing a violation.
+type Problem struct {
+	Type   string         `json:"type"`
+	Title  string         `json:"title"`
+	Status int            `json:"status"`
+	Detail string         `json:"detail"`
+	Check  assert.Check   `json:"check,omitempty"`
+	Issues []assert.Issue `json:"issues,omitempty"`
+}
+
+// NewProblem returns the problem details of the violation.
+func NewProblem(err error) *Problem {
+	status := StatusCode(err)
+
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+	}
+
+	var f *assert.Finding
+
+	if errors.As(err, &f) {
+		p.Check = f.Check
+		p.Issues = f.Issues
+	}
+
+	return p
+}
+
+// StatusCode returns the http status code of the violation.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, assert.ErrBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, assert.ErrPathNotFound), errors.Is(err, assert.ErrNoDocument):
+		return http.StatusNotFound
+	case errors.Is(err, assert.ErrOperationNotFound):
+		return http.StatusMethodNotAllowed
+	}
+
+	var f *assert.Finding
+
+	if errors.As(err, &f) {
+		switch f.Check {
+		case assert.CheckSecurity:
+			return http.StatusUnauthorized
+		case assert.CheckRequestAccept:
+			return http.StatusNotAcceptable
+		case assert.CheckRequestMediaType:
+			return http.StatusUnsupportedMediaType
+		}
+	}
+
+	return http.StatusBadRequest
+}
+
+// ProblemHandler responds the violation with a problem details document.
+func ProblemHandler(ctx echo.Context, err error) error {
+	p := NewProblem(err)
+
+	data, e := json.Marshal(p)
+	if e != nil {
+		return e
+	}
+
+	return ctx.Blob(p.Status, ProblemMediaType, data)
+}
+
+// HTTPErrorHandler returns the violation as an echo.HTTPError, leaving the
+// response to the echo error handler.
+func HTTPErrorHandler(_ echo.Context, err error) error {
+	return &echo.HTTPError{
+		Code:     StatusCode(err),
+		Message:  err.Error(),
+		Internal: err,
+	}
+}
@@ -0,0 +1,195 @@
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ec "github.com/labstack/echo/v4"
+	"gitlab.com/flimzy/testy"
+
+	oapi "github.com/faabiosr/openapi-assert"
+)
+
+func TestProblemHandler(t *testing.T) {
+	type tt struct {
+		method   string
+		path     string
+		header   http.Header
+		body     string
+		config   AssertConfig
+		expected *Problem
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("validation", tt{
+		method: http.MethodPatch,
+		path:   "/api/pets/1",
+		header: http.Header{"Content-Type": {"application/json"}},
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Bad Request",
+			Status: http.StatusBadRequest,
+			Detail: `failed asserting that '{"content-type":"application/json"}' is a valid request header (x-required-header is required)`,
+			Check:  oapi.CheckRequestHeaders,
+			Issues: []oapi.Issue{
+				{Location: "header", Pointer: "/x-required-header", Keyword: "required", Message: "x-required-header is required"},
+			},
+		},
+	})
+
+	tests.Add("body", tt{
+		method: http.MethodPost,
+		path:   "/api/pets",
+		header: http.Header{"Content-Type": {"application/json"}},
+		body:   `{"name": 1}`,
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Bad Request",
+			Status: http.StatusBadRequest,
+			Detail: `failed asserting that '{"name": 1}' is a valid request body (id is required, Invalid type. Expected: string, given: integer, id is required, Must validate all the schemas (allOf))`,
+			Check:  oapi.CheckRequestBody,
+			Issues: []oapi.Issue{
+				{Location: "body", Pointer: "/id", Keyword: "required", Message: "id is required"},
+				{Location: "body", Pointer: "/name", Keyword: "type", Message: "Invalid type. Expected: string, given: integer"},
+				{Location: "body", Pointer: "/id", Keyword: "required", Message: "id is required"},
+				{Location: "body", Pointer: "", Keyword: "allOf", Message: "Must validate all the schemas (allOf)"},
+			},
+		},
+	})
+
+	tests.Add("unknown path", tt{
+		method: http.MethodGet,
+		path:   "/api/unknown",
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Not Found",
+			Status: http.StatusNotFound,
+			Detail: "resource uri does not match",
+		},
+	})
+
+	tests.Add("unknown method", tt{
+		method: http.MethodPut,
+		path:   "/api/pets",
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Method Not Allowed",
+			Status: http.StatusMethodNotAllowed,
+			Detail: "operation does not exists",
+		},
+	})
+
+	tests.Add("media type", tt{
+		method: http.MethodPost,
+		path:   "/api/pets",
+		header: http.Header{"Content-Type": {"text/plain"}},
+		body:   `{"name": "doggo"}`,
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Unsupported Media Type",
+			Status: http.StatusUnsupportedMediaType,
+			Detail: "failed asserting that 'text/plain' is an allowed media type (application/json)",
+			Check:  oapi.CheckRequestMediaType,
+			Issues: []oapi.Issue{
+				{Location: "header", Pointer: "/content-type", Keyword: "consumes", Message: "text/plain is not one of application/json"},
+			},
+		},
+	})
+
+	tests.Add("accept", tt{
+		method: http.MethodGet,
+		path:   "/api/food",
+		header: http.Header{"Accept": {"application/xml"}},
+		config: AssertConfig{Accept: true},
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Not Acceptable",
+			Status: http.StatusNotAcceptable,
+			Detail: "failed asserting that 'application/xml' accepts an allowed media type (application/json)",
+			Check:  oapi.CheckRequestAccept,
+			Issues: []oapi.Issue{
+				{Location: "header", Pointer: "/accept", Keyword: "produces", Message: "application/xml does not accept any of application/json"},
+			},
+		},
+	})
+
+	tests.Add("security", tt{
+		method: http.MethodDelete,
+		path:   "/api/pets/1",
+		config: AssertConfig{Security: true},
+		expected: &Problem{
+			Type:   "about:blank",
+			Title:  "Unauthorized",
+			Status: http.StatusUnauthorized,
+			Detail: "failed asserting that the request is authenticated (api_key or basic)",
+			Check:  oapi.CheckSecurity,
+			Issues: []oapi.Issue{
+				{Location: "header", Pointer: "/x-api-key", Keyword: "security", Message: "credentials are missing"},
+			},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+
+		rec := httptest.NewRecorder()
+
+		c := ec.New().NewContext(req, rec)
+		doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+		cfg := tt.config
+		cfg.Document = doc
+
+		err := AssertWithConfig(cfg)(func(ctx ec.Context) error {
+			return ctx.String(http.StatusOK, "test")
+		})(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ct := rec.Header().Get("Content-Type"); ct != ProblemMediaType {
+			t.Errorf("unexpected content type: %s", ct)
+		}
+
+		got := &Problem{}
+		if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+			t.Fatal(err)
+		}
+
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+
+		if rec.Code != tt.expected.Status {
+			t.Errorf("unexpected status: %d", rec.Code)
+		}
+	})
+}
+
+func TestProblemHandlerWithoutBody(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/api/pets/1", nil)
+		rec := httptest.NewRecorder()
+
+		c := ec.New().NewContext(req, rec)
+		doc, _ := oapi.LoadFromURI("../../fixtures/docs.json")
+
+		err := Assert(doc)(func(ctx ec.Context) error {
+			return ctx.NoContent(http.StatusNoContent)
+		})(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("%s: unexpected status %d: %s", method, rec.Code, rec.Body)
+		}
+	}
+}
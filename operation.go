@@ -0,0 +1,202 @@
+package assert
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownOperation returns an error when no operation has the operationId.
+const ErrUnknownOperation = err("unknown operation id")
+
+// RequestMediaTypeFor asserts request media type against the operation list.
+func (a *Assertions) RequestMediaTypeFor(operationID, mediaType string) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.RequestMediaType(mediaType, op.Path, op.Method)
+}
+
+// ResponseMediaTypeFor asserts response media type against the operation list.
+func (a *Assertions) ResponseMediaTypeFor(operationID, mediaType string) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.ResponseMediaType(mediaType, op.Path, op.Method)
+}
+
+// RequestHeadersFor asserts request headers against the operation headers.
+func (a *Assertions) RequestHeadersFor(operationID string, header http.Header) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.RequestHeaders(header, op.Path, op.Method)
+}
+
+// ResponseHeadersFor asserts response headers against the operation headers.
+func (a *Assertions) ResponseHeadersFor(operationID string, statusCode int, header http.Header) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.ResponseHeaders(header, op.Path, op.Method, statusCode)
+}
+
+// RequestQueryFor asserts request query against the operation query.
+func (a *Assertions) RequestQueryFor(operationID string, query url.Values) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.RequestQuery(query, op.Path, op.Method)
+}
+
+// RequestBodyFor asserts request body against the operation schema.
+func (a *Assertions) RequestBodyFor(operationID string, body io.Reader) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.RequestBody(body, op.Path, op.Method)
+}
+
+// ResponseBodyFor asserts response body against the operation schema.
+func (a *Assertions) ResponseBodyFor(operationID string, statusCode int, body io.Reader) error {
+	r, op, err := a.operationByID(operationID)
+	if err != nil {
+		return err
+	}
+
+	return r.ResponseBody(body, op.Path, op.Method, statusCode)
+}
+
+// operationByID searches the operation by operationId, listing the close
+// matches when not found. The returned assertions use the document declaring
+// the operation.
+func (a *Assertions) operationByID(operationID string) (*Assertions, *Operation, error) {
+	ops, err := documentOperations(a.doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := []string{}
+
+	for _, op := range ops {
+		if op.ID == operationID {
+			return a.withDocument(op.document), op, nil
+		}
+
+		if op.ID != "" {
+			ids = append(ids, op.ID)
+		}
+	}
+
+	matches := closeMatches(operationID, ids)
+	if len(matches) == 0 {
+		return nil, nil, fmt.Errorf("%w '%s'", ErrUnknownOperation, operationID)
+	}
+
+	return nil, nil, fmt.Errorf("%w '%s' (did you mean %s?)", ErrUnknownOperation, operationID, strings.Join(matches, ", "))
+}
+
+// withDocument returns the assertions of the document, or the assertions
+// themselves when the document is nil.
+func (a *Assertions) withDocument(doc Document) *Assertions {
+	if doc == nil {
+		return a
+	}
+
+	r := *a
+	r.doc = doc
+
+	return &r
+}
+
+// closeMatches returns up to three candidates close to the name, ordered by
+// edit distance.
+func closeMatches(name string, candidates []string) []string {
+	type match struct {
+		name     string
+		distance int
+	}
+
+	matches := []match{}
+	lower := strings.ToLower(name)
+
+	for _, c := range candidates {
+		d := levenshtein(lower, strings.ToLower(c))
+		max := len(name) / 3
+
+		if max < 2 {
+			max = 2
+		}
+
+		if d <= max || (lower != "" && strings.Contains(strings.ToLower(c), lower)) {
+			matches = append(matches, match{c, d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+
+	names := []string{}
+
+	for i := 0; i < len(matches) && i < 3; i++ {
+		names = append(names, matches[i].name)
+	}
+
+	return names
+}
+
+// levenshtein returns the edit distance between the strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
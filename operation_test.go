@@ -0,0 +1,94 @@
+package assert
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestAssertionsFor(t *testing.T) {
+	type tt struct {
+		assert func(a *Assertions) error
+		err    string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("unknown operation", tt{
+		assert: func(a *Assertions) error {
+			return a.RequestBodyFor("removePet", strings.NewReader("{}"))
+		},
+		err: "unknown operation id 'removePet'",
+	})
+
+	tests.Add("close matches", tt{
+		assert: func(a *Assertions) error {
+			return a.ResponseBodyFor("findPet", http.StatusOK, strings.NewReader("[]"))
+		},
+		err: "unknown operation id 'findPet' (did you mean findPets, findPetById?)",
+	})
+
+	tests.Add("request media type", tt{
+		assert: func(a *Assertions) error {
+			return a.RequestMediaTypeFor("updatePet", "text/html")
+		},
+		err: "failed asserting that 'text/html' is an allowed media type (application/json, application/xml)",
+	})
+
+	tests.Add("response media type", tt{
+		assert: func(a *Assertions) error {
+			return a.ResponseMediaTypeFor("getPetPhoto", "image/gif")
+		},
+	})
+
+	tests.Add("request headers", tt{
+		assert: func(a *Assertions) error {
+			return a.RequestHeadersFor("updatePet", http.Header{})
+		},
+		err: "failed asserting that '{}' is a valid request header (x-required-header is required)",
+	})
+
+	tests.Add("response headers", tt{
+		assert: func(a *Assertions) error {
+			return a.ResponseHeadersFor("findPets", http.StatusOK, http.Header{"Etag": {"1"}})
+		},
+	})
+
+	tests.Add("request query", tt{
+		assert: func(a *Assertions) error {
+			return a.RequestQueryFor("findPets", url.Values{})
+		},
+		err: "failed asserting that '{}' is a valid request query (limit is required)",
+	})
+
+	tests.Add("request body", tt{
+		assert: func(a *Assertions) error {
+			return a.RequestBodyFor("updatePet", strings.NewReader(`{"id": 1, "name": "doggo"}`))
+		},
+	})
+
+	tests.Add("response body", tt{
+		assert: func(a *Assertions) error {
+			return a.ResponseBodyFor("findPetById", http.StatusOK, strings.NewReader(`{}`))
+		},
+		err: "failed asserting that '{}' is a valid response body (id is required, name is required)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+
+		err := tt.assert(New(doc))
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestCloseMatches(t *testing.T) {
+	got := closeMatches("addpets", []string{"addPet", "findPets", "deletePet", "addFood"})
+
+	if d := testy.DiffInterface([]string{"addPet"}, got); d != nil {
+		t.Error(d)
+	}
+}
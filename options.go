@@ -0,0 +1,173 @@
+package assert
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures the Assertions.
+type Option func(*Assertions)
+
+// defaultAllowedHeaders are the request and response headers accepted in
+// strict mode even when the document does not declare them.
+var defaultAllowedHeaders = []string{
+	"Accept",
+	"Accept-Charset",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Access-Control-Allow-Credentials",
+	"Access-Control-Allow-Headers",
+	"Access-Control-Allow-Methods",
+	"Access-Control-Allow-Origin",
+	"Access-Control-Expose-Headers",
+	"Access-Control-Max-Age",
+	"Age",
+	"Authorization",
+	"Cache-Control",
+	"Connection",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Length",
+	"Content-Type",
+	"Cookie",
+	"Date",
+	"Deprecation",
+	"Etag",
+	"Expires",
+	"Forwarded",
+	"Host",
+	"If-Match",
+	"If-Modified-Since",
+	"If-None-Match",
+	"If-Unmodified-Since",
+	"Last-Modified",
+	"Location",
+	"Origin",
+	"Pragma",
+	"Referer",
+	"Retry-After",
+	"Server",
+	"Set-Cookie",
+	"Strict-Transport-Security",
+	"Sunset",
+	"Traceparent",
+	"Tracestate",
+	"Transfer-Encoding",
+	"User-Agent",
+	"Vary",
+	"Www-Authenticate",
+	"X-Content-Type-Options",
+	"X-B3-Flags",
+	"X-B3-Parentspanid",
+	"X-B3-Sampled",
+	"X-B3-Spanid",
+	"X-B3-Traceid",
+	"X-Forwarded-For",
+	"X-Forwarded-Host",
+	"X-Forwarded-Proto",
+	"X-Real-Ip",
+	"X-Request-Id",
+}
+
+// WithStrict treats undeclared query parameters, headers and body properties
+// as violations. Headers listed in the allow-list are always accepted.
+func WithStrict() Option {
+	return func(a *Assertions) {
+		a.strict = true
+	}
+}
+
+// WithAllowedHeaders adds headers to the strict mode allow-list.
+func WithAllowedHeaders(names ...string) Option {
+	return func(a *Assertions) {
+		for _, name := range names {
+			a.allowedHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithMaxBodySize limits the size in bytes of the request and response bodies,
+// failing with ErrBodyTooLarge when exceeded.
+func WithMaxBodySize(size int64) Option {
+	return func(a *Assertions) {
+		a.maxBodySize = size
+	}
+}
+
+// WithStreaming validates json array bodies item by item. Only the bodies
+// given as readers to RequestBody and ResponseBody are streamed without being
+// loaded into memory; Request and Response buffer the body first, to restore
+// it for the handlers, and validate the buffered items one by one.
+func WithStreaming() Option {
+	return func(a *Assertions) {
+		a.streaming = true
+	}
+}
+
+// WithDecoder registers a body decoder for a media type. The media type can be
+// a full media type like "text/csv", a wildcard like "text/*" or a structured
+// syntax suffix like "+yaml".
+func WithDecoder(mediaType string, decoder Decoder) Option {
+	return func(a *Assertions) {
+		a.decoders[strings.ToLower(mediaType)] = decoder.withContext()
+	}
+}
+
+// WithContextDecoder registers a body decoder receiving the context of the
+// assertion, like WithDecoder.
+func WithContextDecoder(mediaType string, decoder ContextDecoder) Option {
+	return func(a *Assertions) {
+		a.decoders[strings.ToLower(mediaType)] = decoder
+	}
+}
+
+// WithPathPrefix strips the prefix from the request paths before looking them
+// up in the document, for services mounted under a different prefix behind a
+// reverse proxy.
+func WithPathPrefix(prefix string) Option {
+	return func(a *Assertions) {
+		a.pathPrefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithServerValidation asserts the request host and scheme against the host
+// and schemes declared in the document.
+func WithServerValidation() Option {
+	return func(a *Assertions) {
+		a.validateServer = true
+	}
+}
+
+// WithSecurityValidation asserts the request provides the credentials of one
+// of the security requirements of the operation.
+func WithSecurityValidation() Option {
+	return func(a *Assertions) {
+		a.validateSecurity = true
+	}
+}
+
+// WithAcceptValidation asserts the request Accept header against the media
+// types produced by the operation.
+func WithAcceptValidation() Option {
+	return func(a *Assertions) {
+		a.validateAccept = true
+	}
+}
+
+// WithReporter sets the function receiving the non fatal findings, like the
+// usage of deprecated operations and parameters. Findings are handed as
+// *Finding values.
+func WithReporter(reporter func(error)) Option {
+	return func(a *Assertions) {
+		a.reporter = reporter
+	}
+}
+
+// WithSeverity sets the severity of the findings of a check. Findings with a
+// severity other than SeverityError are handed to the reporter instead of
+// failing the assertion.
+func WithSeverity(check Check, severity Severity) Option {
+	return func(a *Assertions) {
+		a.severities[check] = severity
+	}
+}
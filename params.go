@@ -0,0 +1,185 @@
+package assert
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// Params are the request parameters declared by an operation, coerced to the
+// declared types, by location.
+type Params struct {
+	Path   ParamValues
+	Query  ParamValues
+	Header ParamValues
+}
+
+// ParamValues are coerced parameter values by declared name. Integers are
+// int64, numbers float64, booleans bool and arrays []interface{}.
+type ParamValues map[string]interface{}
+
+// String returns the value of a string parameter.
+func (p ParamValues) String(name string) (string, bool) {
+	v, ok := p[name].(string)
+	return v, ok
+}
+
+// Int returns the value of an integer parameter.
+func (p ParamValues) Int(name string) (int64, bool) {
+	v, ok := p[name].(int64)
+	return v, ok
+}
+
+// Float returns the value of a number parameter.
+func (p ParamValues) Float(name string) (float64, bool) {
+	v, ok := p[name].(float64)
+	return v, ok
+}
+
+// Bool returns the value of a boolean parameter.
+func (p ParamValues) Bool(name string) (bool, bool) {
+	v, ok := p[name].(bool)
+	return v, ok
+}
+
+// Strings returns the values of an array parameter of strings.
+func (p ParamValues) Strings(name string) ([]string, bool) {
+	items, ok := p[name].([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	values := []string{}
+
+	for _, item := range items {
+		v, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+
+		values = append(values, v)
+	}
+
+	return values, true
+}
+
+// Params retrieves the parameters of the request declared by the operation,
+// as returned by Operation for the request, coerced to the declared types.
+// Parameters not sent are omitted, and values that cannot be coerced are kept
+// as strings.
+func (a *Assertions) Params(req *http.Request, op *Operation) *Params {
+	params := &Params{
+		Path:   ParamValues{},
+		Query:  ParamValues{},
+		Header: ParamValues{},
+	}
+
+	var path uritemplate.Values
+
+	if tmpl, err := uritemplate.New(op.Path); err == nil {
+		path = tmpl.Match(a.lookupPath(req.URL))
+	}
+
+	query := req.URL.Query()
+
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			if v := path.Get(param.Name); v.Valid() {
+				params.Path[param.Name] = coerceParam(&param, []string{v.String()})
+			}
+		case "query":
+			if values, ok := query[param.Name]; ok {
+				params.Query[param.Name] = coerceParam(&param, values)
+			}
+		case "header":
+			if values := req.Header.Values(param.Name); len(values) > 0 {
+				params.Header[param.Name] = coerceParam(&param, values)
+			}
+		}
+	}
+
+	return params
+}
+
+// coerceParam converts the raw values to the parameter type, splitting
+// arrays according to the collection format. Repeated values of arrays not
+// in the multi format, like repeated header lines, are split and flattened,
+// trimming the spaces around the header items.
+func coerceParam(param *Parameter, values []string) interface{} {
+	s := param.Schema
+	if s == nil || !s.Type.Contains("array") {
+		return coerceValue(s, values[0])
+	}
+
+	if param.CollectionFormat != "multi" {
+		items := []string{}
+
+		for _, v := range values {
+			for _, item := range strings.Split(v, collectionSeparator(param.CollectionFormat)) {
+				if param.In == "header" {
+					item = strings.TrimSpace(item)
+				}
+
+				items = append(items, item)
+			}
+		}
+
+		values = items
+	}
+
+	var items *spec.Schema
+	if s.Items != nil {
+		items = s.Items.Schema
+	}
+
+	result := []interface{}{}
+
+	for _, v := range values {
+		result = append(result, coerceValue(items, v))
+	}
+
+	return result
+}
+
+// collectionSeparator returns the separator of the collection format.
+func collectionSeparator(format string) string {
+	switch format {
+	case "ssv":
+		return " "
+	case "tsv":
+		return "\t"
+	case "pipes":
+		return "|"
+	}
+
+	return ","
+}
+
+// coerceValue converts the value to the schema type, keeping the value when
+// it cannot be converted.
+func coerceValue(schema *spec.Schema, value string) interface{} {
+	if schema == nil {
+		return value
+	}
+
+	switch {
+	case schema.Type.Contains("integer"):
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case schema.Type.Contains("number"):
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case schema.Type.Contains("boolean"):
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,178 @@
+package assert
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"gitlab.com/flimzy/testy"
+)
+
+func TestAssertionsParams(t *testing.T) {
+	type tt struct {
+		method   string
+		path     string
+		header   http.Header
+		expected *Params
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("query", tt{
+		method: http.MethodGet,
+		path:   "/api/pets?limit=10&tags=dog,cat&page=1",
+		expected: &Params{
+			Path:   ParamValues{},
+			Query:  ParamValues{"limit": int64(10), "tags": []interface{}{"dog", "cat"}},
+			Header: ParamValues{},
+		},
+	})
+
+	tests.Add("invalid value", tt{
+		method: http.MethodGet,
+		path:   "/api/pets?limit=ten",
+		expected: &Params{
+			Path:   ParamValues{},
+			Query:  ParamValues{"limit": "ten"},
+			Header: ParamValues{},
+		},
+	})
+
+	tests.Add("path and header", tt{
+		method: http.MethodPatch,
+		path:   "/api/pets/42",
+		header: http.Header{"X-Required-Header": {"value"}},
+		expected: &Params{
+			Path:   ParamValues{"id": int64(42)},
+			Query:  ParamValues{},
+			Header: ParamValues{"X-Required-Header": "value"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc)
+
+		req, _ := http.NewRequest(tt.method, tt.path, nil)
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+
+		op, err := assertions.Operation(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		params := assertions.Params(req, op)
+
+		if d := testy.DiffInterface(tt.expected, params); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestCoerceParam(t *testing.T) {
+	type tt struct {
+		param    *Parameter
+		values   []string
+		expected interface{}
+	}
+
+	array := func(items *spec.Schema) *spec.Schema {
+		return spec.ArrayProperty(items)
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("number", tt{
+		param:    &Parameter{Schema: spec.Float64Property()},
+		values:   []string{"1.5"},
+		expected: 1.5,
+	})
+
+	tests.Add("boolean", tt{
+		param:    &Parameter{Schema: spec.BoolProperty()},
+		values:   []string{"true"},
+		expected: true,
+	})
+
+	tests.Add("pipes", tt{
+		param:    &Parameter{Schema: array(spec.Int64Property()), CollectionFormat: "pipes"},
+		values:   []string{"1|2"},
+		expected: []interface{}{int64(1), int64(2)},
+	})
+
+	tests.Add("repeated", tt{
+		param:    &Parameter{Schema: array(spec.StringProperty())},
+		values:   []string{"a,b", "c"},
+		expected: []interface{}{"a", "b", "c"},
+	})
+
+	tests.Add("repeated header", tt{
+		param:    &Parameter{In: "header", Schema: array(spec.StringProperty())},
+		values:   []string{"a, b", "c"},
+		expected: []interface{}{"a", "b", "c"},
+	})
+
+	tests.Add("multi", tt{
+		param:    &Parameter{Schema: array(spec.StringProperty()), CollectionFormat: "multi"},
+		values:   []string{"a", "b"},
+		expected: []interface{}{"a", "b"},
+	})
+
+	tests.Add("without schema", tt{
+		param:    &Parameter{},
+		values:   []string{"1"},
+		expected: "1",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		if d := testy.DiffInterface(tt.expected, coerceParam(tt.param, tt.values)); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestParamValues(t *testing.T) {
+	values := ParamValues{
+		"name":  "doggo",
+		"id":    int64(1),
+		"score": 1.5,
+		"good":  true,
+		"tags":  []interface{}{"dog", "cat"},
+		"ids":   []interface{}{int64(1)},
+	}
+
+	if v, ok := values.String("name"); !ok || v != "doggo" {
+		t.Errorf("unexpected string: %v", v)
+	}
+
+	if v, ok := values.Int("id"); !ok || v != 1 {
+		t.Errorf("unexpected int: %v", v)
+	}
+
+	if v, ok := values.Float("score"); !ok || v != 1.5 {
+		t.Errorf("unexpected float: %v", v)
+	}
+
+	if v, ok := values.Bool("good"); !ok || !v {
+		t.Errorf("unexpected bool: %v", v)
+	}
+
+	tags, ok := values.Strings("tags")
+	if !ok {
+		t.Error("missing strings")
+	}
+
+	if d := testy.DiffInterface([]string{"dog", "cat"}, tags); d != nil {
+		t.Error(d)
+	}
+
+	if _, ok := values.Strings("ids"); ok {
+		t.Error("unexpected strings of integers")
+	}
+
+	if _, ok := values.Int("name"); ok {
+		t.Error("unexpected int of a string")
+	}
+}
@@ -0,0 +1,184 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/loads"
+)
+
+// ErrNetworkForbidden returns an error when loading a remote reference
+// while the network access is forbidden.
+const ErrNetworkForbidden = err("network access is forbidden")
+
+// docReader reads the raw document of an uri.
+type docReader func(uri string) ([]byte, error)
+
+// ReferenceCache caches the remote documents, shared across loads to fetch
+// each document once. It is safe for concurrent use.
+type ReferenceCache struct {
+	mu   sync.RWMutex
+	docs map[string][]byte
+}
+
+// NewReferenceCache returns an empty ReferenceCache.
+func NewReferenceCache() *ReferenceCache {
+	return &ReferenceCache{docs: map[string][]byte{}}
+}
+
+func (c *ReferenceCache) get(uri string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	data, ok := c.docs[uri]
+
+	return data, ok
+}
+
+func (c *ReferenceCache) set(uri string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.docs[uri] = data
+}
+
+// WithReferenceData resolves the references to the documents by uri, without
+// fragment, from memory.
+func WithReferenceData(docs map[string][]byte) LoadOption {
+	return func(cfg *loadConfig) {
+		if cfg.docs == nil {
+			cfg.docs = map[string][]byte{}
+		}
+
+		for uri, data := range docs {
+			cfg.docs[uri] = data
+		}
+	}
+}
+
+// WithReferenceFiles resolves the references to the uris under the prefixes
+// from the local paths. The rest of the uri is joined to the path, so a
+// prefix ending with a slash maps to a directory.
+func WithReferenceFiles(files map[string]string) LoadOption {
+	return func(cfg *loadConfig) {
+		if cfg.files == nil {
+			cfg.files = map[string]string{}
+		}
+
+		for prefix, path := range files {
+			cfg.files[prefix] = path
+		}
+	}
+}
+
+// WithoutNetwork forbids loading remote documents, failing with
+// ErrNetworkForbidden for the ones not resolved locally.
+func WithoutNetwork() LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.offline = true
+	}
+}
+
+// WithReferenceCache caches the remote documents in the cache, reused by
+// the loads configured with the same cache.
+func WithReferenceCache(cache *ReferenceCache) LoadOption {
+	return func(cfg *loadConfig) {
+		cfg.cache = cache
+	}
+}
+
+// loader returns a document loader converting to json the documents of the
+// configured reader.
+func (cfg *loadConfig) loader(read docReader) loads.DocLoader {
+	read = cfg.reader(read)
+
+	return func(uri string) (json.RawMessage, error) {
+		data, err := read(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		return toJSON(data, "")
+	}
+}
+
+// reader returns a document reader resolving the uris from the configured
+// documents and files first, then the cache, and the read otherwise.
+func (cfg *loadConfig) reader(read docReader) docReader {
+	return func(uri string) ([]byte, error) {
+		if data, ok := cfg.docs[uri]; ok {
+			return data, nil
+		}
+
+		if path, ok := cfg.referenceFile(uri); ok {
+			return os.ReadFile(path)
+		}
+
+		remote := isRemote(uri)
+
+		if remote && cfg.cache != nil {
+			if data, ok := cfg.cache.get(uri); ok {
+				return data, nil
+			}
+		}
+
+		if remote && cfg.offline {
+			return nil, fmt.Errorf("%w (%s)", ErrNetworkForbidden, uri)
+		}
+
+		data, err := read(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		if remote && cfg.cache != nil {
+			cfg.cache.set(uri, data)
+		}
+
+		return data, nil
+	}
+}
+
+// referenceFile returns the local path of the uri, matching the longest
+// configured prefix.
+func (cfg *loadConfig) referenceFile(uri string) (string, bool) {
+	prefixes := []string{}
+
+	for prefix := range cfg.files {
+		if strings.HasPrefix(uri, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	if len(prefixes) == 0 {
+		return "", false
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	path := cfg.files[prefixes[0]]
+
+	if rest := strings.TrimPrefix(uri, prefixes[0]); rest != "" {
+		path = filepath.Join(path, filepath.FromSlash(rest))
+	}
+
+	return path, true
+}
+
+// isRemote reports whether the uri is fetched through the network.
+func isRemote(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "http" || u.Scheme == "https"
+}
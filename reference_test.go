@@ -0,0 +1,127 @@
+package assert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+const errorSchema = `{"type":"object","required":["code"],"properties":{"code":{"type":"integer"},"message":{"type":"string"}}}`
+
+func referenceDoc(uri string) string {
+	return `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "default": {"description": "error", "schema": {"$ref": "` + uri + `#/Error"}}
+        }
+      }
+    }
+  }
+}`
+}
+
+func TestLoadReferences(t *testing.T) {
+	type tt struct {
+		uri  string
+		opts []LoadOption
+		err  string
+	}
+
+	common := []byte("Error:\n  type: object\n  required: [code]\n  properties:\n    code: {type: integer}\n    message: {type: string}\n")
+
+	tests := testy.NewTable()
+
+	tests.Add("reference data", tt{
+		uri: "https://schemas.internal/common.yaml",
+		opts: []LoadOption{
+			WithoutNetwork(),
+			WithReferenceData(map[string][]byte{"https://schemas.internal/common.yaml": common}),
+		},
+	})
+
+	tests.Add("reference files", tt{
+		uri: "https://schemas.internal/common.yaml",
+		opts: []LoadOption{
+			WithoutNetwork(),
+			WithReferenceFiles(map[string]string{
+				"https://schemas.internal/":     "./fixtures/unknown",
+				"https://schemas.internal/comm": "./fixtures/unknown",
+			}),
+			WithReferenceFiles(map[string]string{
+				"https://schemas.internal/common.yaml": "./fixtures/refs/common.yaml",
+			}),
+		},
+	})
+
+	tests.Add("reference directory", tt{
+		uri: "https://schemas.internal/refs/common.yaml",
+		opts: []LoadOption{
+			WithoutNetwork(),
+			WithReferenceFiles(map[string]string{"https://schemas.internal/": "./fixtures"}),
+		},
+	})
+
+	tests.Add("network forbidden", tt{
+		uri:  "https://schemas.internal/common.yaml",
+		opts: []LoadOption{WithoutNetwork()},
+		err:  "unable to expand the document: network access is forbidden (https://schemas.internal/common.yaml)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, err := LoadFromReader(strings.NewReader(referenceDoc(tt.uri)), tt.opts...)
+		testy.Error(t, tt.err, err)
+
+		body, err := doc.ResponseBody("/pets", http.MethodGet, http.StatusInternalServerError)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema, _ := json.Marshal(body)
+		if string(schema) != errorSchema {
+			t.Errorf("unexpected schema: %s", schema)
+		}
+	})
+}
+
+func TestLoadReferenceCache(t *testing.T) {
+	var hits int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		http.ServeFile(w, r, "./fixtures/refs/common.yaml")
+	}))
+	defer ts.Close()
+
+	cache := NewReferenceCache()
+	uri := ts.URL + "/common.yaml"
+
+	for i := 0; i < 2; i++ {
+		if _, err := LoadFromReader(strings.NewReader(referenceDoc(uri)), WithReferenceCache(cache)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := LoadFromReader(strings.NewReader(referenceDoc(uri)), WithReferenceCache(cache), WithoutNetwork())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hits != 1 {
+		t.Errorf("expected a single fetch, got %d", hits)
+	}
+}
+
+func TestLoadFromURIWithoutNetwork(t *testing.T) {
+	_, err := LoadFromURI("https://schemas.internal/docs.json", WithoutNetwork())
+
+	testy.Error(t, "unable to load the document by uri: network access is forbidden (https://schemas.internal/docs.json)", err)
+}
@@ -0,0 +1,219 @@
+package assert
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadEvent reports a reload of a Reloadable document.
+type ReloadEvent struct {
+	// Path is the path of the reloaded document.
+	Path string
+
+	// Time is when the reload happened.
+	Time time.Time
+
+	// Err is the load error, when the previous document was kept.
+	Err error
+}
+
+// Reloadable is a Document loaded from a file, reloaded on demand or when
+// the file changes. The document is swapped atomically, and the Assertions
+// resolve it once per request, so each request is asserted against a single
+// version. A document failing to load or validate is discarded, keeping the
+// previous one.
+type Reloadable struct {
+	path string
+	opts []LoadOption
+	doc  atomic.Value
+
+	mu       sync.Mutex
+	modTime  time.Time
+	handlers []func(ReloadEvent)
+}
+
+var (
+	_ Document = &Reloadable{}
+	_ Resolver = &Reloadable{}
+)
+
+// loaded holds the current document, as atomic.Value requires a consistent
+// concrete type.
+type loaded struct {
+	Document
+}
+
+// NewReloadable loads the document at the path, validated as by
+// WithValidation, returning an error when it cannot be loaded.
+func NewReloadable(path string, opts ...LoadOption) (*Reloadable, error) {
+	r := &Reloadable{
+		path: path,
+		opts: append(opts[:len(opts):len(opts)], WithValidation()),
+	}
+
+	r.modTime = modTime(path)
+
+	doc, err := LoadFromURI(path, r.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r.doc.Store(loaded{doc})
+
+	return r, nil
+}
+
+// OnReload registers a function called with the event of each reload, in
+// the order of the reloads. The function must not call Reload.
+func (r *Reloadable) OnReload(fn func(ReloadEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers = append(r.handlers, fn)
+}
+
+// Reload loads the document again, swapping it when valid. On failure, the
+// previous document is kept and the error returned.
+func (r *Reloadable) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mod := modTime(r.path)
+
+	doc, err := LoadFromURI(r.path, r.opts...)
+	if err == nil {
+		r.doc.Store(loaded{doc})
+		r.modTime = mod
+	}
+
+	event := ReloadEvent{Path: r.path, Time: time.Now(), Err: err}
+
+	for _, fn := range r.handlers {
+		fn(event)
+	}
+
+	return err
+}
+
+// Watch polls the modification time of the file at the interval, reloading
+// the document when it changes, until the context is done. A failed reload,
+// like of a file still being written, is retried at the next tick.
+func (r *Reloadable) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.modified() {
+				_ = r.Reload()
+			}
+		}
+	}
+}
+
+// Current retrieves the current version of the document.
+func (r *Reloadable) Current() Document {
+	return r.doc.Load().(loaded).Document
+}
+
+// Resolve retrieves the current version of the document, resolved again
+// when it is a Resolver itself.
+func (r *Reloadable) Resolve(req *http.Request) (Document, error) {
+	doc := r.Current()
+
+	if resolver, ok := doc.(Resolver); ok {
+		return resolver.Resolve(req)
+	}
+
+	return doc, nil
+}
+
+func (r *Reloadable) modified() bool {
+	mod := modTime(r.path)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !mod.Equal(r.modTime)
+}
+
+// modTime returns the modification time of the file, zero when it cannot be
+// read.
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// Host retrieves the host of the current document, empty when it does not
+// declare a server.
+func (r *Reloadable) Host() string {
+	return documentHost(r.Current())
+}
+
+// Schemes retrieves the schemes of the current document.
+func (r *Reloadable) Schemes() []string {
+	return documentSchemes(r.Current())
+}
+
+// RequestMediaTypes retrives a list of request media types allowed.
+func (r *Reloadable) RequestMediaTypes(path, method string) ([]string, error) {
+	return r.Current().RequestMediaTypes(path, method)
+}
+
+// ResponseMediaTypes retrives a list of response media types allowed.
+func (r *Reloadable) ResponseMediaTypes(path, method string) ([]string, error) {
+	return r.Current().ResponseMediaTypes(path, method)
+}
+
+// RequestHeaders retrieves a list of request headers.
+func (r *Reloadable) RequestHeaders(path, method string) (Headers, error) {
+	return r.Current().RequestHeaders(path, method)
+}
+
+// ResponseHeaders retrieves a list of response headers.
+func (r *Reloadable) ResponseHeaders(path, method string, statusCode int) (Headers, error) {
+	return r.Current().ResponseHeaders(path, method, statusCode)
+}
+
+// RequestQuery retrieves a list of request query.
+func (r *Reloadable) RequestQuery(path, method string) (Query, error) {
+	return r.Current().RequestQuery(path, method)
+}
+
+// RequestBody retrieves the request body.
+func (r *Reloadable) RequestBody(path, method string) (Body, error) {
+	return r.Current().RequestBody(path, method)
+}
+
+// RequestBodyRequired retrieves whether the request body is required.
+func (r *Reloadable) RequestBodyRequired(path, method string) (bool, error) {
+	return requestBodyRequired(r.Current(), path, method)
+}
+
+// ResponseBody retrieves the response body.
+func (r *Reloadable) ResponseBody(path, method string, statusCode int) (Body, error) {
+	return r.Current().ResponseBody(path, method, statusCode)
+}
+
+// Operation retrieves the operation of the path and method.
+func (r *Reloadable) Operation(path, method string) (*Operation, error) {
+	return documentOperation(r.Current(), path, method)
+}
+
+// Operations retrieves the operations of the current document.
+func (r *Reloadable) Operations() []*Operation {
+	ops, _ := documentOperations(r.Current())
+
+	return ops
+}
@@ -0,0 +1,248 @@
+package assert
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/flimzy/testy"
+)
+
+const reloadDoc = `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/%s": {
+      "get": {
+        "operationId": "%s",
+        "responses": {"200": {"description": "ok"}}
+      }
+    }
+  }
+}`
+
+func writeDoc(t *testing.T, path, name string, mod time.Time) {
+	t.Helper()
+
+	data := strings.ReplaceAll(reloadDoc, "%s", name)
+
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func operationIDs(doc Document) []string {
+	ids := []string{}
+
+	for _, op := range doc.(OperationDescriber).Operations() {
+		ids = append(ids, op.ID)
+	}
+
+	return ids
+}
+
+func TestReloadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs.json")
+	now := time.Now()
+
+	writeDoc(t, path, "pets", now)
+
+	doc, err := NewReloadable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []string{}
+
+	doc.OnReload(func(e ReloadEvent) {
+		if e.Err != nil {
+			events = append(events, e.Err.Error())
+			return
+		}
+
+		events = append(events, "reloaded")
+	})
+
+	writeDoc(t, path, "food", now.Add(time.Second))
+
+	if err := doc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := testy.DiffInterface([]string{"food"}, operationIDs(doc)); d != nil {
+		t.Error(d)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"swagger": "2.0"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testy.Error(t, "invalid document: line 1 /info: info is required; line 1 /paths: paths is required", doc.Reload())
+
+	if d := testy.DiffInterface([]string{"food"}, operationIDs(doc)); d != nil {
+		t.Error(d)
+	}
+
+	expected := []string{
+		"reloaded",
+		"invalid document: line 1 /info: info is required; line 1 /paths: paths is required",
+	}
+
+	if d := testy.DiffInterface(expected, events); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestReloadableWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs.json")
+	now := time.Now()
+
+	writeDoc(t, path, "pets", now)
+
+	doc, err := NewReloadable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan ReloadEvent, 1)
+	doc.OnReload(func(e ReloadEvent) { events <- e })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go doc.Watch(ctx, 10*time.Millisecond)
+
+	writeDoc(t, path, "food", now.Add(time.Second))
+
+	select {
+	case e := <-events:
+		if e.Err != nil || e.Path != path {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("document not reloaded")
+	}
+
+	if d := testy.DiffInterface([]string{"food"}, operationIDs(doc)); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestReloadableWatchRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs.json")
+	now := time.Now()
+
+	writeDoc(t, path, "pets", now)
+
+	doc, err := NewReloadable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan ReloadEvent, 100)
+	doc.OnReload(func(e ReloadEvent) {
+		select {
+		case events <- e:
+		default:
+		}
+	})
+
+	mod := now.Add(time.Second)
+
+	if err := os.WriteFile(path, []byte(`{"swagger": "2.0", "info"`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go doc.Watch(ctx, 10*time.Millisecond)
+
+	select {
+	case e := <-events:
+		if e.Err == nil {
+			t.Fatal("expected the half written document to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("document not reloaded")
+	}
+
+	writeDoc(t, path, "food", mod)
+
+	timeout := time.After(5 * time.Second)
+
+	for reloaded := false; !reloaded; {
+		select {
+		case e := <-events:
+			reloaded = e.Err == nil
+		case <-timeout:
+			t.Fatal("failed reload not retried")
+		}
+	}
+
+	if d := testy.DiffInterface([]string{"food"}, operationIDs(doc)); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestReloadableAssertions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs.json")
+	now := time.Now()
+
+	writeDoc(t, path, "pets", now)
+
+	doc, err := NewReloadable(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := New(doc)
+
+	req := httptest.NewRequest(http.MethodGet, "/pets", nil)
+	if _, err := assert.Operation(req); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, _ = assert.Operation(httptest.NewRequest(http.MethodGet, "/pets", nil))
+		}()
+	}
+
+	writeDoc(t, path, "food", now.Add(time.Second))
+
+	if err := doc.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	wg.Wait()
+
+	_, err = assert.Operation(req)
+	testy.Error(t, "resource uri does not match", err)
+}
+
+func TestNewReloadableInvalid(t *testing.T) {
+	_, err := NewReloadable("./fixtures/unknown.json")
+
+	testy.Error(t, "unable to load the document by uri: open ./fixtures/unknown.json: no such file or directory", err)
+}
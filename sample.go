@@ -0,0 +1,734 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	// ErrResponseNotFound returns an error when the operation declares
+	// neither the response status code nor a default response.
+	ErrResponseNotFound = err("response does not exists")
+
+	// ErrSampleMediaType returns an error when the operation consumes no
+	// media type the sample body can be encoded in.
+	ErrSampleMediaType = err("no consumed media type encodes the sample body")
+)
+
+// maxSampleDepth limits the nesting of the generated objects and arrays,
+// past which only the required properties and minimum items are generated,
+// ending recursive schemas.
+const maxSampleDepth = 5
+
+// sampleLetters are the characters of the generated strings.
+const sampleLetters = "abcdefghijklmnopqrstuvwxyz"
+
+// sampleTime is the time around which the dates are generated, so samples
+// do not depend on the current time.
+var sampleTime = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Sample is a request of an operation generated to be valid against the
+// document.
+type Sample struct {
+	Method string
+
+	// Path is the operation path, with the path parameters filled.
+	Path string
+
+	Query  url.Values
+	Header http.Header
+
+	// Body is the body value, nil when the operation has no body.
+	Body interface{}
+}
+
+// NewRequest returns the request of the sample, with the body encoded in
+// json, or sent as is when it is a string of a media type other than json.
+func (s *Sample) NewRequest() (*http.Request, error) {
+	var body io.Reader
+
+	text, isText := s.Body.(string)
+
+	switch {
+	case s.Body == nil:
+	case isText && !isJSON(s.Header.Get("Content-Type")):
+		body = strings.NewReader(text)
+	default:
+		data, err := json.Marshal(s.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	u := &url.URL{Path: s.Path, RawQuery: s.Query.Encode()}
+
+	req, err := http.NewRequest(s.Method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = s.Header.Clone()
+
+	return req, nil
+}
+
+// SampleResponse is a response of an operation generated to be valid
+// against the document.
+type SampleResponse struct {
+	StatusCode int
+	Header     http.Header
+
+	// Body is the body value, nil when the response has no body.
+	Body interface{}
+}
+
+// Generator generates values satisfying the schemas, preferring their
+// example, default or enum values, and otherwise synthesizing data within
+// their formats, patterns and bounds. Generators with the same seed generate
+// the same values. A Generator is not safe for concurrent use.
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator returns a Generator seeded with the seed.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand.New(rand.NewSource(seed))}
+}
+
+// Request generates a request of the operation of the path and method, with
+// every declared parameter and the credentials of the first security
+// requirement. The body is sent in the first consumed json media type, or
+// text media type for string bodies, failing with ErrSampleMediaType when
+// the operation consumes none.
+func (g *Generator) Request(doc Document, path, method string) (*Sample, error) {
+	op, err := documentOperation(doc, path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &Sample{
+		Method: op.Method,
+		Path:   op.Path,
+		Query:  url.Values{},
+		Header: http.Header{},
+	}
+
+	for _, param := range op.Parameters {
+		if param.In == "body" {
+			continue
+		}
+
+		values := g.paramValues(&param)
+
+		switch param.In {
+		case "path":
+			sample.Path = strings.ReplaceAll(sample.Path, "{"+param.Name+"}", url.PathEscape(values[0]))
+		case "query":
+			sample.Query[param.Name] = values
+		case "header":
+			sample.Header[http.CanonicalHeaderKey(param.Name)] = values
+		}
+	}
+
+	if len(op.Security) > 0 {
+		g.credentials(sample, op.Security[0])
+	}
+
+	body, err := doc.RequestBody(path, method)
+	if err != nil && !errors.Is(err, ErrBodyNotFound) {
+		return nil, err
+	}
+
+	if err == nil {
+		schema, _ := body.(*spec.Schema)
+		sample.Body = g.Value(schema)
+	}
+
+	mediaType, err := sampleMediaType(op.Consumes, sample.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaType != "" {
+		sample.Header.Set("Content-Type", mediaType)
+	}
+
+	return sample, nil
+}
+
+// sampleMediaType returns the consumed media type of the sample body: the
+// first text media type for string bodies, otherwise the first json one.
+// Without body, it is the first consumed media type.
+func sampleMediaType(consumes []string, body interface{}) (string, error) {
+	if len(consumes) == 0 {
+		return "", nil
+	}
+
+	if body == nil {
+		return consumes[0], nil
+	}
+
+	if _, ok := body.(string); ok {
+		for _, mediaType := range consumes {
+			if strings.HasPrefix(mediaType, "text/") {
+				return mediaType, nil
+			}
+		}
+	}
+
+	for _, mediaType := range consumes {
+		if isJSON(mediaType) {
+			return mediaType, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w (%s)", ErrSampleMediaType, strings.Join(consumes, ", "))
+}
+
+// Response generates a response of the operation of the path and method
+// with the status code, using the default response when the code is not
+// declared. The body is the response example of the media type, generated
+// from the response schema when there is none, and the media type is sent
+// as content type.
+func (g *Generator) Response(doc Document, path, method string, statusCode int, mediaType string) (*SampleResponse, error) {
+	op, err := documentOperation(doc, path, method)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *Response
+
+	for i := range op.Responses {
+		switch op.Responses[i].StatusCode {
+		case statusCode:
+			res = &op.Responses[i]
+		case 0:
+			if res == nil {
+				res = &op.Responses[i]
+			}
+		}
+	}
+
+	if res == nil {
+		return nil, fmt.Errorf("%w (%s %s %d)", ErrResponseNotFound, op.Method, op.Path, statusCode)
+	}
+
+	sample := &SampleResponse{StatusCode: statusCode, Header: http.Header{}}
+
+	names := []string{}
+	for name := range res.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		h := res.Headers[name]
+		param := &Parameter{
+			CollectionFormat: h.CollectionFormat,
+			Schema:           simpleSchema(h.SimpleSchema, h.CommonValidations),
+		}
+
+		sample.Header[http.CanonicalHeaderKey(name)] = g.paramValues(param)
+	}
+
+	if example, ok := res.Examples[mediaType]; ok {
+		sample.Body = example
+	} else if res.Schema != nil {
+		sample.Body = g.Value(res.Schema)
+	}
+
+	if mediaType != "" {
+		sample.Header.Set("Content-Type", mediaType)
+	}
+
+	return sample, nil
+}
+
+// paramValues generates the raw values of a parameter, joining the arrays
+// according to the collection format.
+func (g *Generator) paramValues(param *Parameter) []string {
+	value := g.Value(param.Schema)
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return []string{formatValue(value)}
+	}
+
+	values := []string{}
+	for _, item := range items {
+		values = append(values, formatValue(item))
+	}
+
+	if param.CollectionFormat == "multi" {
+		return values
+	}
+
+	return []string{strings.Join(values, collectionSeparator(param.CollectionFormat))}
+}
+
+// credentials adds the credentials of the security schemes to the sample.
+func (g *Generator) credentials(sample *Sample, schemes []SecurityScheme) {
+	for _, scheme := range schemes {
+		switch {
+		case scheme.Type == "basic":
+			credentials := g.letters(8) + ":" + g.letters(8)
+			sample.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+		case scheme.Type == "oauth2":
+			sample.Header.Set("Authorization", "Bearer "+g.letters(16))
+		case scheme.Type == "apiKey" && scheme.In == "query":
+			sample.Query.Set(scheme.ParamName, g.letters(16))
+		case scheme.Type == "apiKey":
+			sample.Header.Set(scheme.ParamName, g.letters(16))
+		}
+	}
+}
+
+// Value generates a value satisfying the schema, nil for a nil schema.
+func (g *Generator) Value(schema *spec.Schema) interface{} {
+	return g.value(schema, 0)
+}
+
+func (g *Generator) value(schema *spec.Schema, depth int) interface{} {
+	switch {
+	case schema == nil:
+		return nil
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[g.rand.Intn(len(schema.Enum))]
+	case len(schema.AllOf) > 0:
+		return g.allOf(schema, depth)
+	}
+
+	switch sampleType(schema) {
+	case "object":
+		return g.object(schema, depth)
+	case "array":
+		return g.array(schema, depth)
+	case "integer":
+		return g.integer(schema)
+	case "number":
+		return g.number(schema)
+	case "boolean":
+		return g.rand.Intn(2) == 1
+	case "null":
+		return nil
+	}
+
+	return g.string(schema)
+}
+
+// sampleType returns the first type of the schema, inferred from its
+// keywords when not declared.
+func sampleType(schema *spec.Schema) string {
+	switch {
+	case len(schema.Type) > 0:
+		return schema.Type[0]
+	case len(schema.Properties) > 0:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	}
+
+	return "string"
+}
+
+// allOf merges the objects generated for the composed schemas.
+func (g *Generator) allOf(schema *spec.Schema, depth int) interface{} {
+	schemas := schema.AllOf
+
+	if len(schema.Type) > 0 || len(schema.Properties) > 0 {
+		own := *schema
+		own.AllOf = nil
+		schemas = append(schemas[:len(schemas):len(schemas)], own)
+	}
+
+	merged := map[string]interface{}{}
+
+	for _, s := range schemas {
+		v := g.value(&s, depth)
+
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		for name, value := range object {
+			merged[name] = value
+		}
+	}
+
+	return merged
+}
+
+func (g *Generator) object(schema *spec.Schema, depth int) interface{} {
+	object := map[string]interface{}{}
+	required := map[string]bool{}
+
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	names := []string{}
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if depth >= maxSampleDepth && !required[name] {
+			continue
+		}
+
+		property := schema.Properties[name]
+		object[name] = g.value(&property, depth+1)
+	}
+
+	return object
+}
+
+func (g *Generator) array(schema *spec.Schema, depth int) interface{} {
+	var items *spec.Schema
+	if schema.Items != nil {
+		items = schema.Items.Schema
+	}
+
+	min, max := int64(1), int64(3)
+
+	if schema.MinItems != nil {
+		min = *schema.MinItems
+		max = min + 2
+	}
+
+	if schema.MaxItems != nil && *schema.MaxItems < max {
+		max = *schema.MaxItems
+		if min > max {
+			min = max
+		}
+	}
+
+	if depth >= maxSampleDepth {
+		max = min
+	}
+
+	n := min + g.rand.Int63n(max-min+1)
+	values := []interface{}{}
+
+	for tries := 0; int64(len(values)) < n && tries < 10*int(n); tries++ {
+		v := g.value(items, depth+1)
+
+		if schema.UniqueItems && containsValue(values, v) {
+			continue
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	data, _ := json.Marshal(v)
+
+	for _, value := range values {
+		if d, _ := json.Marshal(value); bytes.Equal(d, data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *Generator) integer(schema *spec.Schema) interface{} {
+	min, max := int64(0), int64(100)
+
+	if schema.Minimum != nil {
+		min = int64(math.Ceil(*schema.Minimum))
+		if schema.ExclusiveMinimum && float64(min) == *schema.Minimum {
+			min++
+		}
+
+		if schema.Maximum == nil {
+			max = min + 100
+		}
+	}
+
+	if schema.Maximum != nil {
+		max = int64(math.Floor(*schema.Maximum))
+		if schema.ExclusiveMaximum && float64(max) == *schema.Maximum {
+			max--
+		}
+
+		if schema.Minimum == nil {
+			min = max - 100
+			if max >= 0 && min < 0 {
+				min = 0
+			}
+		}
+	}
+
+	step := int64(1)
+	if schema.MultipleOf != nil && *schema.MultipleOf >= 1 {
+		step = int64(*schema.MultipleOf)
+	}
+
+	low := int64(math.Ceil(float64(min) / float64(step)))
+	high := int64(math.Floor(float64(max) / float64(step)))
+
+	if high < low {
+		return min
+	}
+
+	return (low + g.rand.Int63n(high-low+1)) * step
+}
+
+func (g *Generator) number(schema *spec.Schema) interface{} {
+	min, max := 0.0, 100.0
+
+	if schema.Minimum != nil {
+		min = *schema.Minimum
+		if schema.Maximum == nil {
+			max = min + 100
+		}
+	}
+
+	if schema.Maximum != nil {
+		max = *schema.Maximum
+		if schema.Minimum == nil {
+			min = math.Min(0, max-100)
+		}
+	}
+
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step := *schema.MultipleOf
+		low, high := math.Ceil(min/step), math.Floor(max/step)
+
+		if schema.ExclusiveMinimum && low*step == min {
+			low++
+		}
+
+		if schema.ExclusiveMaximum && high*step == max {
+			high--
+		}
+
+		if high < low {
+			return min
+		}
+
+		return (low + float64(g.rand.Int63n(int64(high-low)+1))) * step
+	}
+
+	// a value strictly between the bounds, rounded to cents when it keeps it
+	// within them
+	v := min + (max-min)*(0.1+0.8*g.rand.Float64())
+	if rounded := math.Round(v*100) / 100; rounded > min && rounded < max {
+		v = rounded
+	}
+
+	return v
+}
+
+func (g *Generator) string(schema *spec.Schema) interface{} {
+	if v, ok := g.format(schema.Format); ok {
+		return v
+	}
+
+	if schema.Pattern != "" {
+		if v, ok := g.pattern(schema.Pattern); ok {
+			return v
+		}
+	}
+
+	min, max := int64(1), int64(10)
+
+	if schema.MinLength != nil {
+		min = *schema.MinLength
+		max = min + 9
+	}
+
+	if schema.MaxLength != nil && *schema.MaxLength < max {
+		max = *schema.MaxLength
+		if min > max {
+			min = max
+		}
+	}
+
+	return g.letters(int(min + g.rand.Int63n(max-min+1)))
+}
+
+// format generates a string of the format, if known.
+func (g *Generator) format(format string) (string, bool) {
+	switch format {
+	case "date-time":
+		return g.time().Format(time.RFC3339), true
+	case "date":
+		return g.time().Format("2006-01-02"), true
+	case "email":
+		return g.letters(8) + "@example.com", true
+	case "hostname":
+		return g.letters(8) + ".example.com", true
+	case "uri", "url":
+		return "https://example.com/" + g.letters(8), true
+	case "uuid":
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), true
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+g.rand.Intn(254)), true
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+g.rand.Intn(0xfffe)), true
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(g.letters(8))), true
+	}
+
+	return "", false
+}
+
+func (g *Generator) time() time.Time {
+	return sampleTime.Add(time.Duration(g.rand.Int63n(365*24)) * time.Hour)
+}
+
+func (g *Generator) letters(n int) string {
+	b := make([]byte, n)
+
+	for i := range b {
+		b[i] = sampleLetters[g.rand.Intn(len(sampleLetters))]
+	}
+
+	return string(b)
+}
+
+// pattern generates a string matching the regular expression, failing for
+// the expressions it cannot generate.
+func (g *Generator) pattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+
+	if !g.regexp(&b, re.Simplify()) {
+		return "", false
+	}
+
+	return b.String(), true
+}
+
+func (g *Generator) regexp(b *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine,
+		syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+		return true
+	case syntax.OpCharClass:
+		return g.charClass(b, re.Rune)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(sampleLetters[g.rand.Intn(len(sampleLetters))])
+		return true
+	case syntax.OpCapture:
+		return g.regexp(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.regexp(b, sub) {
+				return false
+			}
+		}
+
+		return true
+	case syntax.OpAlternate:
+		return g.regexp(b, re.Sub[g.rand.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := repeatBounds(re)
+
+		for i := min + g.rand.Intn(max-min+1); i > 0; i-- {
+			if !g.regexp(b, re.Sub[0]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// repeatBounds returns the bounds of a repetition, limiting the unbounded
+// ones.
+func repeatBounds(re *syntax.Regexp) (int, int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, 3
+	case syntax.OpPlus:
+		return 1, 3
+	case syntax.OpQuest:
+		return 0, 1
+	}
+
+	if re.Max < 0 {
+		return re.Min, re.Min + 3
+	}
+
+	return re.Min, re.Max
+}
+
+// charClass writes a rune of the class, given as pairs of range bounds.
+func (g *Generator) charClass(b *strings.Builder, ranges []rune) bool {
+	if len(ranges) == 0 {
+		return false
+	}
+
+	i := 2 * g.rand.Intn(len(ranges)/2)
+	low, high := ranges[i], ranges[i+1]
+
+	// keep to printable ascii when the range allows it
+	if low < ' ' && high >= ' ' {
+		low = ' '
+	}
+
+	if high > '~' && low <= '~' {
+		high = '~'
+	}
+
+	b.WriteRune(low + rune(g.rand.Intn(int(high-low)+1)))
+
+	return true
+}
+
+// formatValue returns the raw value of a parameter.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,362 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/xeipuuv/gojsonschema"
+	"gitlab.com/flimzy/testy"
+)
+
+func TestGeneratorValue(t *testing.T) {
+	type tt struct {
+		schema   string
+		expected interface{}
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("example", tt{
+		schema:   `{"type": "integer", "default": 2, "example": 1}`,
+		expected: float64(1),
+	})
+
+	tests.Add("default", tt{
+		schema:   `{"type": "string", "enum": ["a", "b"], "default": "b"}`,
+		expected: "b",
+	})
+
+	tests.Add("enum", tt{schema: `{"type": "string", "enum": ["dog", "cat"]}`})
+	tests.Add("date-time", tt{schema: `{"type": "string", "format": "date-time"}`})
+	tests.Add("date", tt{schema: `{"type": "string", "format": "date"}`})
+	tests.Add("email", tt{schema: `{"type": "string", "format": "email"}`})
+	tests.Add("hostname", tt{schema: `{"type": "string", "format": "hostname"}`})
+	tests.Add("uri", tt{schema: `{"type": "string", "format": "uri"}`})
+	tests.Add("uuid", tt{schema: `{"type": "string", "format": "uuid"}`})
+	tests.Add("ipv4", tt{schema: `{"type": "string", "format": "ipv4"}`})
+	tests.Add("ipv6", tt{schema: `{"type": "string", "format": "ipv6"}`})
+	tests.Add("pattern", tt{schema: `{"type": "string", "pattern": "^[A-Z]{3}-\\d{2,4}(-(x|y))?$"}`})
+	tests.Add("length", tt{schema: `{"type": "string", "minLength": 12, "maxLength": 14}`})
+	tests.Add("integer bounds", tt{schema: `{"type": "integer", "minimum": 10, "maximum": 12, "exclusiveMinimum": true}`})
+	tests.Add("integer maximum", tt{schema: `{"type": "integer", "maximum": -5, "exclusiveMaximum": true}`})
+	tests.Add("integer multiple", tt{schema: `{"type": "integer", "minimum": 1, "multipleOf": 7}`})
+	tests.Add("number bounds", tt{schema: `{"type": "number", "minimum": 0.1, "maximum": 0.2, "exclusiveMaximum": true}`})
+	tests.Add("number multiple", tt{schema: `{"type": "number", "maximum": 10, "multipleOf": 0.5}`})
+	tests.Add("boolean", tt{schema: `{"type": "boolean"}`})
+	tests.Add("unique items", tt{schema: `{"type": "array", "items": {"type": "boolean"}, "minItems": 2, "uniqueItems": true}`})
+	tests.Add("max items", tt{schema: `{"type": "array", "items": {"type": "string"}, "maxItems": 1}`})
+
+	tests.Add("object", tt{
+		schema: `{
+  "type": "object",
+  "required": ["id"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "integer", "format": "int64"},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "owner": {"properties": {"email": {"type": "string", "format": "email"}}}
+  }
+}`,
+	})
+
+	tests.Add("all of", tt{
+		schema: `{
+  "allOf": [
+    {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}},
+    {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}
+  ]
+}`,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		var schema spec.Schema
+		if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+			t.Fatal(err)
+		}
+
+		for seed := int64(0); seed < 50; seed++ {
+			v := NewGenerator(seed).Value(&schema)
+
+			if tt.expected != nil {
+				if d := testy.DiffInterface(tt.expected, v); d != nil {
+					t.Fatal(d)
+				}
+			}
+
+			result, err := gojsonschema.Validate(
+				gojsonschema.NewStringLoader(tt.schema),
+				gojsonschema.NewGoLoader(v),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !result.Valid() {
+				t.Fatalf("seed %d generated the invalid value %#v: %v", seed, v, result.Errors())
+			}
+		}
+	})
+}
+
+func TestGeneratorRequest(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+	assert := New(doc)
+
+	for _, op := range doc.(OperationDescriber).Operations() {
+		op := op
+
+		t.Run(op.Method+" "+op.Path, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				sample, err := NewGenerator(seed).Request(doc, op.Path, op.Method)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				req, err := sample.NewRequest()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := assert.Request(req); err != nil {
+					t.Fatalf("seed %d: %v", seed, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratorRequestSample(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+
+	sample, err := NewGenerator(1).Request(doc, "/api/pets/1", http.MethodPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := testy.DiffInterface(testy.Snapshot(t), sample); d != nil {
+		t.Error(d)
+	}
+
+	again, _ := NewGenerator(1).Request(doc, "/api/pets/1", http.MethodPatch)
+
+	if d := testy.DiffInterface(sample, again); d != nil {
+		t.Errorf("same seed generated another sample: %s", d)
+	}
+}
+
+func TestGeneratorRequestNotFound(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+
+	_, err := NewGenerator(1).Request(doc, "/api/unknown", http.MethodGet)
+	testy.Error(t, "resource uri does not match", err)
+}
+
+func TestGeneratorResponse(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+	assert := New(doc)
+
+	for _, op := range doc.(OperationDescriber).Operations() {
+		op := op
+
+		for _, code := range append(op.StatusCodes(), http.StatusInternalServerError) {
+			code := code
+
+			t.Run(fmt.Sprintf("%s %s %d", op.Method, op.Path, code), func(t *testing.T) {
+				mediaType := ""
+				if len(op.Produces) > 0 {
+					mediaType = op.Produces[0]
+				}
+
+				sample, err := NewGenerator(1).Response(doc, op.Path, op.Method, code, mediaType)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if sample.Body == nil {
+					path := strings.ReplaceAll(op.Path, "{id}", "1")
+
+					if err := assert.ResponseHeaders(sample.Header, path, op.Method, code); err != nil {
+						t.Fatal(err)
+					}
+
+					return
+				}
+
+				data, _ := json.Marshal(sample.Body)
+				if s, ok := sample.Body.(string); ok {
+					data = []byte(s)
+				}
+
+				res := &http.Response{
+					StatusCode: sample.StatusCode,
+					Header:     sample.Header,
+					Body:       io.NopCloser(bytes.NewReader(data)),
+					Request:    httptest.NewRequest(op.Method, strings.ReplaceAll(op.Path, "{id}", "1"), nil),
+				}
+
+				if err := assert.Response(res); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	}
+}
+
+func TestGeneratorResponseExamples(t *testing.T) {
+	type tt struct {
+		code      int
+		mediaType string
+		expected  *SampleResponse
+		err       string
+	}
+
+	doc, _ := LoadFromURI("./fixtures/examples.json")
+
+	tests := testy.NewTable()
+
+	tests.Add("example", tt{
+		code:      http.StatusOK,
+		mediaType: "application/json",
+		expected: &SampleResponse{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"application/json"},
+				"X-Rate-Limit": {"0"},
+			},
+			Body: map[string]interface{}{"id": float64(1), "name": "doggo"},
+		},
+	})
+
+	tests.Add("text example", tt{
+		code:      http.StatusOK,
+		mediaType: "text/plain",
+		expected: &SampleResponse{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type": {"text/plain"},
+				"X-Rate-Limit": {"0"},
+			},
+			Body: "doggo",
+		},
+	})
+
+	tests.Add("default response", tt{
+		code:      http.StatusNotFound,
+		mediaType: "application/problem+json",
+		expected: &SampleResponse{
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{"Content-Type": {"application/problem+json"}},
+			Body:       map[string]interface{}{"message": "unexpected"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		sample, err := NewGenerator(1).Response(doc, "/api/pets/1", http.MethodGet, tt.code, tt.mediaType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if d := testy.DiffInterface(tt.expected, sample); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestGeneratorResponseNotFound(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/examples.json")
+
+	_, err := NewGenerator(1).Response(doc, "/api/pets/1", http.MethodPut, http.StatusInternalServerError, "")
+	testy.Error(t, "response does not exists (PUT /api/pets/{id} 500)", err)
+}
+
+func TestGeneratorRequestMediaType(t *testing.T) {
+	type tt struct {
+		consumes string
+		schema   string
+		expected string
+		err      string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("first json media type", tt{
+		consumes: `["application/xml", "application/vnd.pet+json"]`,
+		schema:   `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+		expected: "application/vnd.pet+json",
+	})
+
+	tests.Add("text media type of string bodies", tt{
+		consumes: `["application/json", "text/plain"]`,
+		schema:   `{"type": "string"}`,
+		expected: "text/plain",
+	})
+
+	tests.Add("no json media type", tt{
+		consumes: `["application/xml"]`,
+		schema:   `{"type": "object"}`,
+		err:      "no consumed media type encodes the sample body (application/xml)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, err := LoadFromReader(strings.NewReader(`{
+			"swagger": "2.0",
+			"info": {"title": "pets", "version": "1.0"},
+			"paths": {
+				"/pets": {
+					"post": {
+						"consumes": ` + tt.consumes + `,
+						"parameters": [{"name": "pet", "in": "body", "schema": ` + tt.schema + `}],
+						"responses": {"200": {"description": "ok"}}
+					}
+				}
+			}
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sample, err := NewGenerator(1).Request(doc, "/pets", http.MethodPost)
+		testy.Error(t, tt.err, err)
+
+		if err != nil {
+			return
+		}
+
+		if got := sample.Header.Get("Content-Type"); got != tt.expected {
+			t.Errorf("want %s, got %s", tt.expected, got)
+		}
+
+		req, err := sample.NewRequest()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := New(doc).Request(req); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestGeneratorRequestBodyError(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+
+	_, err := NewGenerator(1).Request(failingBody{doc.(*swagger)}, "/api/pets", http.MethodPost)
+	testy.Error(t, "broken body", err)
+}
+
+// failingBody is a document failing to retrieve the request bodies.
+type failingBody struct {
+	*swagger
+}
+
+func (failingBody) RequestBody(string, string) (Body, error) {
+	return nil, errors.New("broken body")
+}
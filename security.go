@@ -0,0 +1,85 @@
+package assert
+
+import (
+	"net/http"
+	"strings"
+)
+
+// assertSecurity asserts the request provides the credentials of one of the
+// security requirements of the operation, nil when the request matches no
+// operation. Only the presence of the credentials is checked.
+func (a *Assertions) assertSecurity(req *http.Request, op *Operation) error {
+	if op == nil || len(op.Security) == 0 {
+		return nil
+	}
+
+	names := []string{}
+
+	for _, requirement := range op.Security {
+		if credentialsProvided(req, requirement) {
+			return nil
+		}
+
+		schemes := []string{}
+		for _, scheme := range requirement {
+			schemes = append(schemes, scheme.Name)
+		}
+
+		names = append(names, strings.Join(schemes, " and "))
+	}
+
+	location, pointer := credentialsLocation(op.Security[0][0])
+
+	issue := Issue{
+		Location: location,
+		Pointer:  pointer,
+		Keyword:  "security",
+		Message:  "credentials are missing",
+	}
+
+	return failIssues([]Issue{issue}, `the request is authenticated (%s)`, strings.Join(names, " or "))
+}
+
+// credentialsProvided reports whether the request provides the credentials of
+// every scheme of the requirement. Unknown scheme types are accepted.
+func credentialsProvided(req *http.Request, requirement []SecurityScheme) bool {
+	for _, scheme := range requirement {
+		switch scheme.Type {
+		case "basic":
+			if !hasAuthorization(req, "basic") {
+				return false
+			}
+		case "oauth2":
+			if !hasAuthorization(req, "bearer") {
+				return false
+			}
+		case "apiKey":
+			if scheme.In == "query" && req.URL.Query().Get(scheme.ParamName) == "" {
+				return false
+			}
+
+			if scheme.In == "header" && req.Header.Get(scheme.ParamName) == "" {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// hasAuthorization reports whether the authorization header uses the scheme.
+func hasAuthorization(req *http.Request, scheme string) bool {
+	fields := strings.Fields(req.Header.Get("Authorization"))
+
+	return len(fields) == 2 && strings.EqualFold(fields[0], scheme)
+}
+
+// credentialsLocation returns the location and pointer of the credentials of
+// the scheme.
+func credentialsLocation(scheme SecurityScheme) (string, string) {
+	if scheme.Type == "apiKey" {
+		return scheme.In, "/" + escapePointer(strings.ToLower(scheme.ParamName))
+	}
+
+	return "header", "/authorization"
+}
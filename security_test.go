@@ -0,0 +1,132 @@
+package assert
+
+import (
+	"net/http"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestAssertionsSecurity(t *testing.T) {
+	type tt struct {
+		method string
+		header http.Header
+		opts   []Option
+		err    string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("disabled by default", tt{
+		method: http.MethodDelete,
+		opts:   []Option{},
+	})
+
+	tests.Add("not secured", tt{
+		method: http.MethodGet,
+	})
+
+	tests.Add("missing credentials", tt{
+		method: http.MethodDelete,
+		err:    "failed asserting that the request is authenticated (api_key or basic)",
+	})
+
+	tests.Add("api key", tt{
+		method: http.MethodDelete,
+		header: http.Header{"X-Api-Key": {"secret"}},
+	})
+
+	tests.Add("basic", tt{
+		method: http.MethodDelete,
+		header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+	})
+
+	tests.Add("bearer", tt{
+		method: http.MethodDelete,
+		header: http.Header{"Authorization": {"Bearer token"}},
+		err:    "failed asserting that the request is authenticated (api_key or basic)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		if tt.opts == nil {
+			tt.opts = []Option{WithSecurityValidation()}
+		}
+
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, tt.opts...)
+
+		req, _ := http.NewRequest(tt.method, "/api/pets/1", nil)
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+
+		err := assertions.Request(req)
+
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestAssertionsRequestAccept(t *testing.T) {
+	type tt struct {
+		accept string
+		err    string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("empty", tt{})
+
+	tests.Add("exact", tt{
+		accept: "application/xml",
+	})
+
+	tests.Add("wildcard", tt{
+		accept: "text/html, application/*;q=0.8",
+	})
+
+	tests.Add("any", tt{
+		accept: "image/png, */*;q=0.1",
+	})
+
+	tests.Add("not acceptable", tt{
+		accept: "image/png, application/json;q=0",
+		err:    "failed asserting that 'image/png, application/json;q=0' accepts an allowed media type (application/json, application/xml, text/xml, text/html)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc)
+
+		err := assertions.RequestAccept(tt.accept, "/api/pets", http.MethodGet)
+
+		testy.Error(t, tt.err, err)
+	})
+}
+
+func TestAssertionsRequestAcceptValidation(t *testing.T) {
+	type tt struct {
+		opts []Option
+		err  string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("disabled by default", tt{})
+
+	tests.Add("enabled", tt{
+		opts: []Option{WithAcceptValidation()},
+		err:  "failed asserting that 'application/xml' accepts an allowed media type (application/json)",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+		assertions := New(doc, tt.opts...)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/food", nil)
+		req.Header.Set("Accept", "application/xml")
+
+		err := assertions.Request(req)
+
+		testy.Error(t, tt.err, err)
+	})
+}
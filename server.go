@@ -0,0 +1,96 @@
+package assert
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// lookupPath returns the path used to look up the request in the document,
+// without query string, scheme, host and the configured prefix.
+func (a *Assertions) lookupPath(u *url.URL) string {
+	path := u.Path
+
+	if a.pathPrefix != "" && (path == a.pathPrefix || strings.HasPrefix(path, a.pathPrefix+"/")) {
+		path = strings.TrimPrefix(path, a.pathPrefix)
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return path
+}
+
+// assertServer asserts the request host and scheme against the document,
+// when the server validation is enabled and the document declares a server.
+func (a *Assertions) assertServer(req *http.Request) error {
+	server, ok := a.doc.(ServerDescriber)
+	if !a.validateServer || !ok {
+		return nil
+	}
+
+	if host := server.Host(); host != "" && !hostMatches(requestHost(req), host) {
+		issue := Issue{Location: "url", Pointer: "/host", Keyword: "host", Message: fmt.Sprintf("%s is not %s", requestHost(req), host)}
+
+		return failIssues([]Issue{issue}, `'%s' is an allowed host (%s)`, requestHost(req), host)
+	}
+
+	schemes := server.Schemes()
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	scheme := requestScheme(req)
+
+	for _, v := range schemes {
+		if strings.EqualFold(v, scheme) {
+			return nil
+		}
+	}
+
+	issue := Issue{Location: "url", Pointer: "/scheme", Keyword: "schemes", Message: fmt.Sprintf("%s is not one of %s", scheme, strings.Join(schemes, ", "))}
+
+	return failIssues([]Issue{issue}, `'%s' is an allowed scheme (%s)`, scheme, strings.Join(schemes, ", "))
+}
+
+// requestHost returns the host the request was sent to.
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+
+	return req.URL.Host
+}
+
+// requestScheme returns the scheme the request was sent with, considering
+// the X-Forwarded-Proto header set by reverse proxies.
+func requestScheme(req *http.Request) string {
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return strings.ToLower(strings.TrimSpace(strings.Split(proto, ",")[0]))
+	}
+
+	if req.URL.Scheme != "" {
+		return strings.ToLower(req.URL.Scheme)
+	}
+
+	if req.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// hostMatches compares the hosts, ignoring the port when the document host
+// does not declare one.
+func hostMatches(host, expected string) bool {
+	if _, _, err := net.SplitHostPort(expected); err != nil {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+
+	return strings.EqualFold(host, expected)
+}
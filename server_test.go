@@ -0,0 +1,86 @@
+package assert
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestLookupPath(t *testing.T) {
+	type tt struct {
+		uri      string
+		opts     []Option
+		expected string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("absolute url", tt{
+		uri:      "http://petstore.swagger.io/api/pets?limit=1",
+		expected: "/api/pets",
+	})
+
+	tests.Add("relative url", tt{
+		uri:      "api/pets",
+		expected: "/api/pets",
+	})
+
+	tests.Add("with prefix", tt{
+		uri:      "/svc/api/pets",
+		opts:     []Option{WithPathPrefix("/svc")},
+		expected: "/api/pets",
+	})
+
+	tests.Add("with partial prefix", tt{
+		uri:      "/svcs/api/pets",
+		opts:     []Option{WithPathPrefix("/svc")},
+		expected: "/svcs/api/pets",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		u, _ := url.Parse(tt.uri)
+
+		if got := New(nil, tt.opts...).lookupPath(u); got != tt.expected {
+			t.Errorf("want %s, got %s", tt.expected, got)
+		}
+	})
+}
+
+func TestRequestScheme(t *testing.T) {
+	type tt struct {
+		uri      string
+		header   http.Header
+		expected string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("default", tt{
+		uri:      "/api/pets",
+		expected: "http",
+	})
+
+	tests.Add("from url", tt{
+		uri:      "HTTPS://petstore.swagger.io/api/pets",
+		expected: "https",
+	})
+
+	tests.Add("forwarded", tt{
+		uri:      "/api/pets",
+		header:   http.Header{"X-Forwarded-Proto": {"https, http"}},
+		expected: "https",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		req, _ := http.NewRequest(http.MethodGet, tt.uri, nil)
+		for k, v := range tt.header {
+			req.Header[k] = v
+		}
+
+		if got := requestScheme(req); got != tt.expected {
+			t.Errorf("want %s, got %s", tt.expected, got)
+		}
+	})
+}
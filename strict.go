@@ -0,0 +1,180 @@
+package assert
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// undeclaredNames returns the sorted names that are not declared in the
+// schema list. The names accepted by allowed are skipped.
+func undeclaredNames(names []string, schema map[string]interface{}, allowed func(string) bool) []string {
+	undeclared := []string{}
+
+	for _, name := range names {
+		key := strings.ToLower(name)
+
+		if _, ok := schema[key]; ok && key != "required" {
+			continue
+		}
+
+		if allowed != nil && allowed(name) {
+			continue
+		}
+
+		undeclared = append(undeclared, name)
+	}
+
+	sort.Strings(undeclared)
+
+	return undeclared
+}
+
+// undeclaredProperties walks the data using the schema and returns the json
+// pointer of every object property the schema does not declare.
+func undeclaredProperties(schema *spec.Schema, data interface{}, pointer string) []string {
+	if schema == nil {
+		return nil
+	}
+
+	switch value := data.(type) {
+	case map[string]interface{}:
+		return undeclaredObjectProperties(schema, value, pointer)
+	case []interface{}:
+		return undeclaredItemProperties(schema, value, pointer)
+	}
+
+	return nil
+}
+
+func undeclaredObjectProperties(schema *spec.Schema, data map[string]interface{}, pointer string) []string {
+	schemas := flattenSchemas(schema)
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	undeclared := []string{}
+
+	for _, key := range keys {
+		path := pointer + "/" + escapePointer(key)
+		subs := []*spec.Schema{}
+		declared := false
+
+		for _, s := range schemas {
+			subs = append(subs, propertySchemas(s, key)...)
+
+			if s.AdditionalProperties != nil && s.AdditionalProperties.Allows {
+				declared = true
+			}
+		}
+
+		if len(subs) > 0 {
+			undeclared = append(undeclared, undeclaredProperties(mergeSchemas(subs), data[key], path)...)
+			continue
+		}
+
+		if !declared {
+			undeclared = append(undeclared, path)
+		}
+	}
+
+	return undeclared
+}
+
+func undeclaredItemProperties(schema *spec.Schema, data []interface{}, pointer string) []string {
+	schemas := flattenSchemas(schema)
+	undeclared := []string{}
+
+	for i, item := range data {
+		subs := []*spec.Schema{}
+
+		for _, s := range schemas {
+			if s.Items == nil {
+				continue
+			}
+
+			sub := s.Items.Schema
+
+			if sub == nil && i < len(s.Items.Schemas) {
+				sub = &s.Items.Schemas[i]
+			}
+
+			if sub != nil {
+				subs = append(subs, sub)
+			}
+		}
+
+		if len(subs) == 0 {
+			continue
+		}
+
+		path := fmt.Sprintf("%s/%d", pointer, i)
+		undeclared = append(undeclared, undeclaredProperties(mergeSchemas(subs), item, path)...)
+	}
+
+	return undeclared
+}
+
+// mergeSchemas returns a schema composed of all the schemas, so a value
+// declared by several of them is walked once.
+func mergeSchemas(schemas []*spec.Schema) *spec.Schema {
+	if len(schemas) == 1 {
+		return schemas[0]
+	}
+
+	merged := &spec.Schema{}
+
+	for _, s := range schemas {
+		merged.AllOf = append(merged.AllOf, *s)
+	}
+
+	return merged
+}
+
+// flattenSchemas returns the schema and all the schemas it is composed of.
+func flattenSchemas(schema *spec.Schema) []*spec.Schema {
+	schemas := []*spec.Schema{schema}
+
+	for _, composed := range [][]spec.Schema{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for i := range composed {
+			schemas = append(schemas, flattenSchemas(&composed[i])...)
+		}
+	}
+
+	return schemas
+}
+
+// propertySchemas returns the schemas that declare the property name.
+func propertySchemas(schema *spec.Schema, name string) []*spec.Schema {
+	schemas := []*spec.Schema{}
+
+	if sub, ok := schema.Properties[name]; ok {
+		schemas = append(schemas, &sub)
+	}
+
+	for pattern, sub := range schema.PatternProperties {
+		if ok, err := regexp.MatchString(pattern, name); ok && err == nil {
+			sub := sub
+			schemas = append(schemas, &sub)
+		}
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		if _, ok := schema.Properties[name]; !ok && len(schemas) == 0 {
+			schemas = append(schemas, schema.AdditionalProperties.Schema)
+		}
+	}
+
+	return schemas
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,88 @@
+package assert
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"gitlab.com/flimzy/testy"
+)
+
+func TestUndeclaredProperties(t *testing.T) {
+	type tt struct {
+		schema   string
+		data     string
+		expected []string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("declared", tt{
+		schema:   `{"properties": {"name": {"type": "string"}}}`,
+		data:     `{"name": "doggo"}`,
+		expected: []string{},
+	})
+
+	tests.Add("undeclared", tt{
+		schema:   `{"properties": {"name": {"type": "string"}}}`,
+		data:     `{"name": "doggo", "a/b": 1}`,
+		expected: []string{"/a~1b"},
+	})
+
+	tests.Add("composed", tt{
+		schema:   `{"allOf": [{"properties": {"id": {}}}, {"properties": {"name": {}}}]}`,
+		data:     `{"id": 1, "name": "doggo", "tag": "dog"}`,
+		expected: []string{"/tag"},
+	})
+
+	tests.Add("composed nested", tt{
+		schema:   `{"allOf": [{"properties": {"o": {"properties": {"x": {}}}}}, {"properties": {"o": {"properties": {"x": {}}}}}]}`,
+		data:     `{"o": {"x": 1, "y": 2}}`,
+		expected: []string{"/o/y"},
+	})
+
+	tests.Add("composed nested across schemas", tt{
+		schema:   `{"allOf": [{"properties": {"o": {"properties": {"x": {}}}}}, {"properties": {"o": {"properties": {"y": {}}}}}]}`,
+		data:     `{"o": {"x": 1, "y": 2, "z": 3}}`,
+		expected: []string{"/o/z"},
+	})
+
+	tests.Add("composed items", tt{
+		schema:   `{"allOf": [{"items": {"properties": {"id": {}}}}, {"items": {"properties": {"id": {}}}}]}`,
+		data:     `[{"id": 1, "name": "doggo"}]`,
+		expected: []string{"/0/name"},
+	})
+
+	tests.Add("additional properties", tt{
+		schema:   `{"additionalProperties": {"properties": {"id": {}}}}`,
+		data:     `{"a": {"id": 1}, "b": {"name": "doggo"}}`,
+		expected: []string{"/b/name"},
+	})
+
+	tests.Add("pattern properties", tt{
+		schema:   `{"patternProperties": {"^x-": {}}}`,
+		data:     `{"x-id": 1, "id": 1}`,
+		expected: []string{"/id"},
+	})
+
+	tests.Add("items", tt{
+		schema:   `{"items": {"properties": {"id": {}}}}`,
+		data:     `[{"id": 1}, {"id": 2, "name": "doggo"}]`,
+		expected: []string{"/1/name"},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		var (
+			schema spec.Schema
+			data   interface{}
+		)
+
+		_ = json.Unmarshal([]byte(tt.schema), &schema)
+		_ = json.Unmarshal([]byte(tt.data), &data)
+
+		got := undeclaredProperties(&schema, data, "")
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
@@ -1,16 +1,19 @@
 package assert
 
 import (
-	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/go-openapi/jsonpointer"
 	"github.com/go-openapi/loads"
 	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
 	"github.com/yosida95/uritemplate/v3"
 )
 
@@ -20,55 +23,145 @@ const (
 
 	// ErrBodyNotFound returns an error when body does not exists.
 	ErrBodyNotFound = err("body does not exists")
+
+	// ErrPathNotFound returns an error when no document path matches the uri.
+	ErrPathNotFound = err("resource uri does not match")
+
+	// ErrOperationNotFound returns an error when the path does not declare
+	// the method.
+	ErrOperationNotFound = err("operation does not exists")
 )
 
+// methods are the http methods of a path item, in document order.
+var methods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+}
+
 // swagger stores the loaded swagger spec.
 type swagger struct {
 	spec *spec.Swagger
+
+	// orig is the spec before expansion and raw the document it was loaded
+	// from, locating the examples. Both are kept only when loaded
+	// WithExamples.
+	orig *spec.Swagger
+	raw  []byte
 }
 
 var _ Document = &swagger{}
 
 // LoadFromURI loads and expands swagger document by uri.
-func LoadFromURI(uri string) (Document, error) {
-	doc, err := loads.Spec(uri)
+func LoadFromURI(uri string, opts ...LoadOption) (Document, error) {
+	cfg := newLoadConfig(opts)
+
+	raw, err := cfg.reader(swag.LoadFromFileOrHTTP)(uri)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrSwaggerLoad, err)
 	}
 
-	return load(doc)
+	return load(raw, uri, cfg.loader(swag.LoadFromFileOrHTTP), cfg)
 }
 
-// LoadFromReader loads and expand swagger document from io.Reader.
-func LoadFromReader(r io.Reader) (Document, error) {
-	data, err := ioutil.ReadAll(r)
+// LoadFromReader loads and expand swagger document from io.Reader, in json
+// or yaml, detected unless set by WithFormat or WithContentType.
+func LoadFromReader(r io.Reader, opts ...LoadOption) (Document, error) {
+	cfg := newLoadConfig(opts)
+
+	raw, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrSwaggerLoad, err)
 	}
 
-	doc, err := loads.Analyzed(data, "")
+	return load(raw, "", cfg.loader(swag.LoadFromFileOrHTTP), cfg)
+}
+
+// LoadFromFS loads and expands swagger document from the file system, like
+// an embed.FS, resolving the relative references inside the file system.
+func LoadFromFS(fsys fs.FS, name string, opts ...LoadOption) (Document, error) {
+	cfg := newLoadConfig(opts)
+	uri := fsURI(name)
+
+	raw, err := cfg.reader(fsReader(fsys))(uri)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrSwaggerLoad, err)
 	}
 
-	return load(doc)
+	return load(raw, uri, cfg.loader(fsReader(fsys)), cfg)
 }
 
-func load(doc *loads.Document) (Document, error) {
-	doc, err := doc.Expanded()
+// load analyzes and expands the raw document, resolving the references
+// relative to the base uri with the loader, and validating it when
+// configured.
+func load(raw []byte, base string, loader loads.DocLoader, cfg *loadConfig) (Document, error) {
+	data, err := toJSON(raw, cfg.format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrSwaggerLoad, err)
+	}
+
+	doc, err := loads.Analyzed(data, "", loads.WithDocLoader(loader))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrSwaggerLoad, err)
+	}
+
+	if cfg.validate {
+		if err := validateStructure(doc.Raw(), raw); err != nil {
+			return nil, err
+		}
+	}
+
+	expanded, err := doc.Expanded(&spec.ExpandOptions{RelativeBase: base})
 	if err != nil {
 		return nil, fmt.Errorf("unable to expand the document: %w", err)
 	}
 
-	return &swagger{doc.Spec()}, nil
+	if cfg.validate {
+		if err := validateSemantics(expanded.Spec(), raw); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &swagger{spec: expanded.Spec()}
+
+	if cfg.examples {
+		s.orig = doc.Spec()
+		s.raw = raw
+	}
+
+	return s, nil
 }
 
-// findPath searches for an uri in document and returns the path.
+// Host retrieves the host serving the API, empty when not declared.
+func (s *swagger) Host() string {
+	return s.spec.Host
+}
+
+// Schemes retrieves the transfer protocols of the API.
+func (s *swagger) Schemes() []string {
+	return s.spec.Schemes
+}
+
+// findPath searches for an uri in document and returns the path. Path
+// templates are also accepted, and literal paths are preferred over the
+// templated ones.
 func (s *swagger) findPath(uri string) (string, error) {
-	for path := range s.spec.Paths.Paths {
-		tmpl, err := uritemplate.New(s.spec.BasePath + path)
+	basePath := strings.TrimSuffix(s.spec.BasePath, "/")
+
+	for _, path := range s.paths() {
+		if basePath+path == uri {
+			return strings.ReplaceAll(path, "/", "~1"), nil
+		}
+	}
+
+	for _, path := range s.paths() {
+		tmpl, err := uritemplate.New(basePath + path)
 		if err != nil {
-			return "", fmt.Errorf("resource uri does not match: %w", err)
+			return "", fmt.Errorf("%w: %s", ErrPathNotFound, err)
 		}
 
 		if tmpl.Regexp().MatchString(uri) {
@@ -76,7 +169,32 @@ func (s *swagger) findPath(uri string) (string, error) {
 		}
 	}
 
-	return "", errors.New("resource uri does not match")
+	return "", ErrPathNotFound
+}
+
+// paths returns the document paths, sorted by the number of template
+// variables and then alphabetically.
+func (s *swagger) paths() []string {
+	paths := []string{}
+
+	if s.spec.Paths == nil {
+		return paths
+	}
+
+	for path := range s.spec.Paths.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		vi, vj := strings.Count(paths[i], "{"), strings.Count(paths[j], "{")
+		if vi != vj {
+			return vi < vj
+		}
+
+		return paths[i] < paths[j]
+	})
+
+	return paths
 }
 
 // findNode searches a node using segments in the schema.
@@ -102,6 +220,10 @@ func (s *swagger) mediaTypes(path, method, segment string) ([]string, error) {
 		return []string{}, err
 	}
 
+	if !s.hasOperation(path, method) {
+		return []string{}, ErrOperationNotFound
+	}
+
 	method = strings.ToLower(method)
 
 	data, err := s.findNode("paths", path, method, segment)
@@ -149,6 +271,10 @@ func (s *swagger) requestParameters(path, method string) ([]spec.Parameter, erro
 		return params, err
 	}
 
+	if !s.hasOperation(path, method) {
+		return params, ErrOperationNotFound
+	}
+
 	data, _ := s.findNode("paths", path, "parameters")
 	if data != nil {
 		params = append(params, data.([]spec.Parameter)...)
@@ -296,6 +422,22 @@ func (s *swagger) RequestBody(path, method string) (Body, error) {
 	return nil, ErrBodyNotFound
 }
 
+// RequestBodyRequired retrieves whether the request body is required.
+func (s *swagger) RequestBodyRequired(path, method string) (bool, error) {
+	params, err := s.requestParameters(path, method)
+	if err != nil {
+		return false, err
+	}
+
+	for _, param := range params {
+		if param.In == "body" {
+			return param.Required, nil
+		}
+	}
+
+	return false, ErrBodyNotFound
+}
+
 // ResponseBody retrieves the response body.
 func (s *swagger) ResponseBody(path, method string, statusCode int) (Body, error) {
 	res, err := s.response(path, method, statusCode)
@@ -309,3 +451,267 @@ func (s *swagger) ResponseBody(path, method string, statusCode int) (Body, error
 
 	return nil, ErrBodyNotFound
 }
+
+// Operation retrieves the operation of the path and method.
+func (s *swagger) Operation(path, method string) (*Operation, error) {
+	path, err := s.findPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	path = strings.ReplaceAll(path, "~1", "/")
+	method = strings.ToUpper(method)
+
+	op := s.operation(path, method)
+	if op == nil {
+		return nil, ErrOperationNotFound
+	}
+
+	return op, nil
+}
+
+// hasOperation reports whether the escaped path declares the method.
+func (s *swagger) hasOperation(path, method string) bool {
+	return s.operation(strings.ReplaceAll(path, "~1", "/"), strings.ToUpper(method)) != nil
+}
+
+// Operations retrieves all the document operations, sorted by path and
+// method.
+func (s *swagger) Operations() []*Operation {
+	ops := []*Operation{}
+
+	paths := s.paths()
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range methods {
+			if op := s.operation(path, method); op != nil {
+				ops = append(ops, op)
+			}
+		}
+	}
+
+	return ops
+}
+
+// operation builds the operation of the path template and method.
+func (s *swagger) operation(path, method string) *Operation {
+	item := s.spec.Paths.Paths[path]
+
+	op := operationOf(item, method)
+	if op == nil {
+		return nil
+	}
+
+	o := &Operation{
+		ID:          op.ID,
+		Method:      method,
+		Path:        strings.TrimSuffix(s.spec.BasePath, "/") + path,
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  op.Deprecated,
+		Sunset:      extensionString(op.Extensions, "x-sunset"),
+		Consumes:    op.Consumes,
+		Produces:    op.Produces,
+		Parameters:  []Parameter{},
+		Responses:   []Response{},
+	}
+
+	if len(o.Consumes) == 0 {
+		o.Consumes = s.spec.Consumes
+	}
+
+	if len(o.Produces) == 0 {
+		o.Produces = s.spec.Produces
+	}
+
+	o.Security = s.security(op)
+
+	for _, param := range mergeParameters(item.Parameters, op.Parameters) {
+		o.Parameters = append(o.Parameters, Parameter{
+			Name:             param.Name,
+			In:               param.In,
+			Description:      param.Description,
+			Required:         param.Required,
+			CollectionFormat: param.CollectionFormat,
+			Deprecated:       extensionBool(param.Extensions, "x-deprecated"),
+			Schema:           parameterSchema(param),
+		})
+	}
+
+	if op.Responses == nil {
+		return o
+	}
+
+	codes := []int{}
+	for code := range op.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		o.Responses = append(o.Responses, operationResponse(code, op.Responses.StatusCodeResponses[code]))
+	}
+
+	if op.Responses.Default != nil {
+		o.Responses = append(o.Responses, operationResponse(0, *op.Responses.Default))
+	}
+
+	return o
+}
+
+// security resolves the security requirements of the operation, falling back
+// to the document ones.
+func (s *swagger) security(op *spec.Operation) [][]SecurityScheme {
+	requirements := op.Security
+	if requirements == nil {
+		requirements = s.spec.Security
+	}
+
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	security := [][]SecurityScheme{}
+
+	for _, requirement := range requirements {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		schemes := []SecurityScheme{}
+
+		for _, name := range names {
+			scheme := SecurityScheme{Name: name, Scopes: requirement[name]}
+
+			if def, ok := s.spec.SecurityDefinitions[name]; ok && def != nil {
+				scheme.Type = def.Type
+				scheme.In = def.In
+				scheme.ParamName = def.Name
+			}
+
+			schemes = append(schemes, scheme)
+		}
+
+		security = append(security, schemes)
+	}
+
+	return security
+}
+
+// operationOf returns the operation of the path item for the upper case
+// method, nil when not declared.
+func operationOf(item spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodOptions:
+		return item.Options
+	case http.MethodHead:
+		return item.Head
+	case http.MethodPatch:
+		return item.Patch
+	}
+
+	return nil
+}
+
+func operationResponse(code int, res spec.Response) Response {
+	return Response{
+		StatusCode:  code,
+		Description: res.Description,
+		Schema:      res.Schema,
+		Headers:     res.Headers,
+		Examples:    res.Examples,
+	}
+}
+
+// mergeParameters merges the path and operation parameters, with the
+// operation ones overriding the path ones with the same name and location.
+func mergeParameters(pathParams, opParams []spec.Parameter) []spec.Parameter {
+	params := []spec.Parameter{}
+
+	for _, param := range pathParams {
+		overridden := false
+
+		for _, p := range opParams {
+			if p.Name == param.Name && p.In == param.In {
+				overridden = true
+			}
+		}
+
+		if !overridden {
+			params = append(params, param)
+		}
+	}
+
+	return append(params, opParams...)
+}
+
+// parameterSchema returns the body parameter schema, or the json schema
+// equivalent of the other parameters.
+func parameterSchema(param spec.Parameter) *spec.Schema {
+	if param.In == "body" {
+		return param.Schema
+	}
+
+	schema := simpleSchema(param.SimpleSchema, param.CommonValidations)
+	schema.Description = param.Description
+
+	return schema
+}
+
+func simpleSchema(simple spec.SimpleSchema, validations spec.CommonValidations) *spec.Schema {
+	schema := &spec.Schema{}
+
+	if simple.Type != "" {
+		schema.Type = spec.StringOrArray{simple.Type}
+	}
+
+	schema.Format = simple.Format
+	schema.Default = simple.Default
+	schema.Example = simple.Example
+	schema.Nullable = simple.Nullable
+	schema.Maximum = validations.Maximum
+	schema.ExclusiveMaximum = validations.ExclusiveMaximum
+	schema.Minimum = validations.Minimum
+	schema.ExclusiveMinimum = validations.ExclusiveMinimum
+	schema.MaxLength = validations.MaxLength
+	schema.MinLength = validations.MinLength
+	schema.Pattern = validations.Pattern
+	schema.MaxItems = validations.MaxItems
+	schema.MinItems = validations.MinItems
+	schema.UniqueItems = validations.UniqueItems
+	schema.MultipleOf = validations.MultipleOf
+	schema.Enum = validations.Enum
+
+	if simple.Items != nil {
+		items := simpleSchema(simple.Items.SimpleSchema, simple.Items.CommonValidations)
+		schema.Items = &spec.SchemaOrArray{Schema: items}
+	}
+
+	return schema
+}
+
+func extensionString(ext spec.Extensions, key string) string {
+	v, _ := ext.GetString(key)
+
+	return v
+}
+
+func extensionBool(ext spec.Extensions, key string) bool {
+	v, _ := ext.GetBool(key)
+
+	return v
+}
@@ -1,14 +1,19 @@
 package assert
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/go-openapi/loads"
+	"github.com/go-openapi/spec"
 	"gitlab.com/flimzy/testy"
 )
 
@@ -43,6 +48,7 @@ func TestLoadFromURI(t *testing.T) {
 func TestLoadFromReader(t *testing.T) {
 	type tt struct {
 		reader io.Reader
+		opts   []LoadOption
 		err    string
 	}
 
@@ -75,8 +81,41 @@ func TestLoadFromReader(t *testing.T) {
 		}
 	})
 
+	tests.Add("yaml file", func() interface{} {
+		f, _ := os.Open("./fixtures/docs.yaml")
+
+		return tt{
+			reader: f,
+		}
+	})
+
+	tests.Add("yaml content type", func() interface{} {
+		f, _ := os.Open("./fixtures/docs.yaml")
+
+		return tt{
+			reader: f,
+			opts:   []LoadOption{WithContentType("application/x-yaml; charset=utf-8")},
+		}
+	})
+
+	tests.Add("flow style yaml", tt{
+		reader: strings.NewReader(`{swagger: "2.0", info: {title: pets, version: "1.0"}, paths: {}}`),
+	})
+
+	tests.Add("invalid yaml", tt{
+		reader: strings.NewReader("swagger: [2.0"),
+		opts:   []LoadOption{WithFormat(FormatYAML)},
+		err:    "unable to load the document by uri: yaml: line 1: did not find expected ',' or ']'",
+	})
+
+	tests.Add("json format", tt{
+		reader: strings.NewReader(`{swagger: "2.0"}`),
+		opts:   []LoadOption{WithFormat(FormatJSON)},
+		err:    "unable to load the document by uri: invalid character 's' looking for beginning of object key string",
+	})
+
 	tests.Run(t, func(t *testing.T, tt tt) {
-		_, err := LoadFromReader(tt.reader)
+		_, err := LoadFromReader(tt.reader, tt.opts...)
 		testy.Error(t, tt.err, err)
 	})
 }
@@ -85,12 +124,27 @@ func TestFindPath(t *testing.T) {
 	doc, _ := loads.Spec("./fixtures/invalid-path.json")
 	doc, _ = doc.Expanded()
 
-	s := &swagger{doc.Spec()}
+	s := &swagger{spec: doc.Spec()}
 
 	_, err := s.findPath("/api/food/a")
 	testy.Error(t, "resource uri does not match: unacceptable variable name: /api/food/{_", err)
 }
 
+func TestFindPathRootBasePath(t *testing.T) {
+	doc, _ := loads.Analyzed([]byte(`{"swagger": "2.0", "basePath": "/", "paths": {"/food": {}}}`), "")
+
+	s := &swagger{spec: doc.Spec()}
+
+	got, err := s.findPath("/food")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "~1food" {
+		t.Errorf("want ~1food, got %s", got)
+	}
+}
+
 func TestRequestMediaTypes(t *testing.T) {
 	type tt struct {
 		path   string
@@ -330,6 +384,51 @@ func TestRequestBody(t *testing.T) {
 	})
 }
 
+func TestRequestBodyRequired(t *testing.T) {
+	type tt struct {
+		path     string
+		method   string
+		expected bool
+		err      string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("invalid path", tt{
+		path:   "/some",
+		method: http.MethodPost,
+		err:    "resource uri does not match",
+	})
+
+	tests.Add("not exists", tt{
+		path:   "/api/pets",
+		method: http.MethodGet,
+		err:    "body does not exists",
+	})
+
+	tests.Add("optional", tt{
+		path:   "/api/food",
+		method: http.MethodPost,
+	})
+
+	tests.Add("required", tt{
+		path:     "/api/pets",
+		method:   http.MethodPost,
+		expected: true,
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+
+		got, err := doc.(BodyRequirer).RequestBodyRequired(tt.path, tt.method)
+		testy.Error(t, tt.err, err)
+
+		if got != tt.expected {
+			t.Errorf("want %v, got %v", tt.expected, got)
+		}
+	})
+}
+
 func TestResponseBody(t *testing.T) {
 	type tt struct {
 		path   string
@@ -373,3 +472,209 @@ func TestResponseBody(t *testing.T) {
 		}
 	})
 }
+
+func TestOperation(t *testing.T) {
+	type tt struct {
+		path     string
+		method   string
+		expected *Operation
+		err      string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("invalid path", tt{
+		path:   "/some",
+		method: http.MethodPost,
+		err:    "resource uri does not match",
+	})
+
+	tests.Add("invalid method", tt{
+		path:   "/api/food",
+		method: http.MethodPut,
+		err:    "operation does not exists",
+	})
+
+	tests.Add("success", tt{
+		path:   "/api/pets/1",
+		method: "delete",
+		expected: &Operation{
+			ID:          "deletePet",
+			Method:      http.MethodDelete,
+			Path:        "/api/pets/{id}",
+			Description: "deletes a single pet based on the ID supplied",
+			Consumes:    []string{"application/json"},
+			Produces:    []string{"application/json"},
+			Parameters: []Parameter{
+				{
+					Name:        "id",
+					In:          "path",
+					Description: "Override the shared ID parameter",
+					Required:    true,
+					Schema: func() *spec.Schema {
+						s := spec.Int64Property()
+						s.Description = "Override the shared ID parameter"
+
+						return s
+					}(),
+				},
+			},
+			Responses: []Response{
+				{StatusCode: 204, Description: "pet deleted"},
+				{Description: "unexpected error", Schema: func() *spec.Schema {
+					doc, _ := LoadFromURI("./fixtures/docs.json")
+					s, _ := doc.ResponseBody("/api/pets/1", http.MethodDelete, 0)
+
+					return s.(*spec.Schema)
+				}()},
+			},
+			Security: [][]SecurityScheme{
+				{{Name: "api_key", Type: "apiKey", In: "header", ParamName: "X-Api-Key", Scopes: []string{}}},
+				{{Name: "basic", Type: "basic", Scopes: []string{}}},
+			},
+		},
+	})
+
+	tests.Add("path template", tt{
+		path:   "/api/pets/{id}/photo",
+		method: http.MethodGet,
+		expected: func() *Operation {
+			doc, _ := LoadFromURI("./fixtures/docs.json")
+			op, _ := doc.(OperationDescriber).Operation("/api/pets/1/photo", http.MethodGet)
+
+			return op
+		}(),
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/docs.json")
+
+		got, err := doc.(OperationDescriber).Operation(tt.path, tt.method)
+		testy.Error(t, tt.err, err)
+
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestOperations(t *testing.T) {
+	doc, _ := LoadFromURI("./fixtures/docs.json")
+
+	got := []string{}
+
+	for _, op := range doc.(OperationDescriber).Operations() {
+		got = append(got, fmt.Sprintf("%s %s %s %v", op.Method, op.Path, op.ID, op.StatusCodes()))
+	}
+
+	expected := []string{
+		"GET /api/food findFood [304]",
+		"POST /api/food addFood [204]",
+		"GET /api/pets findPets [200]",
+		"POST /api/pets addPet [200]",
+		"GET /api/pets/{id} findPetById [200]",
+		"DELETE /api/pets/{id} deletePet [204]",
+		"PATCH /api/pets/{id} updatePet [200 204]",
+		"GET /api/pets/{id}/photo getPetPhoto []",
+	}
+
+	if d := testy.DiffInterface(expected, got); d != nil {
+		t.Error(d)
+	}
+}
+
+func TestLoadFromFS(t *testing.T) {
+	type tt struct {
+		fsys fs.FS
+		name string
+		err  string
+	}
+
+	root := `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {"description": "pets", "schema": {"$ref": "schemas/pet.json"}}
+        }
+      }
+    }
+  }
+}`
+
+	tests := testy.NewTable()
+
+	tests.Add("file not found", tt{
+		fsys: fstest.MapFS{},
+		name: "docs.json",
+		err:  "unable to load the document by uri: open docs.json: file does not exist",
+	})
+
+	tests.Add("reference not found", tt{
+		fsys: fstest.MapFS{
+			"specs/docs.json": {Data: []byte(root)},
+		},
+		name: "specs/docs.json",
+		err:  "unable to expand the document: open specs/schemas/pet.json: file does not exist",
+	})
+
+	tests.Add("reference outside the file system", tt{
+		fsys: fstest.MapFS{
+			"docs.json": {Data: []byte(strings.Replace(root, "schemas/pet.json", "http://example.com/pet.json", 1))},
+		},
+		name: "docs.json",
+		err:  "unable to expand the document: reference 'http://example.com/pet.json' is outside the file system",
+	})
+
+	tests.Add("success", tt{
+		fsys: fstest.MapFS{
+			"specs/docs.json":        {Data: []byte(root)},
+			"specs/schemas/pet.json": {Data: []byte(`{"type": "object", "properties": {"tag": {"$ref": "tag.json"}}}`)},
+			"specs/schemas/tag.json": {Data: []byte(`{"type": "string"}`)},
+		},
+		name: "specs/docs.json",
+	})
+
+	tests.Add("yaml references", tt{
+		fsys: fstest.MapFS{
+			"docs.json":        {Data: []byte(strings.Replace(root, "schemas/pet.json", "schemas/pet.yaml", 1))},
+			"schemas/pet.yaml": {Data: []byte("type: object\nproperties:\n  tag:\n    type: string\n")},
+		},
+		name: "docs.json",
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, err := LoadFromFS(tt.fsys, tt.name)
+		testy.Error(t, tt.err, err)
+
+		body, err := doc.ResponseBody("/pets", http.MethodGet, http.StatusOK)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		schema, _ := json.Marshal(body)
+		expected := `{"type":"object","properties":{"tag":{"type":"string"}}}`
+
+		if string(schema) != expected {
+			t.Errorf("unexpected schema: %s", schema)
+		}
+	})
+}
+
+func TestLoadYAMLFixture(t *testing.T) {
+	jsonDoc, _ := LoadFromURI("./fixtures/docs.json")
+
+	f, _ := os.Open("./fixtures/docs.yaml")
+	defer f.Close()
+
+	yamlDoc, err := LoadFromReader(f, WithValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := testy.DiffInterface(jsonDoc.(OperationDescriber).Operations(), yamlDoc.(OperationDescriber).Operations()); d != nil {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,341 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecIssue is a problem found validating a document.
+type SpecIssue struct {
+	// Pointer is the json pointer of the invalid node.
+	Pointer string
+
+	// Line is the line of the invalid node in the document, zero when
+	// unknown.
+	Line int
+
+	// Message describes the problem.
+	Message string
+}
+
+func (i SpecIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Pointer, i.Message)
+	}
+
+	return fmt.Sprintf("line %d %s: %s", i.Line, i.Pointer, i.Message)
+}
+
+// ValidationError is the error of an invalid document, listing its issues
+// sorted by line.
+type ValidationError struct {
+	Issues []SpecIssue
+}
+
+func (e *ValidationError) Error() string {
+	issues := []string{}
+
+	for _, issue := range e.Issues {
+		issues = append(issues, issue.String())
+	}
+
+	return fmt.Sprintf("invalid document: %s", strings.Join(issues, "; "))
+}
+
+// compositions are the validator error types reporting a failed schema
+// composition, superseded by the errors of the composed schemas.
+var compositions = map[string]bool{
+	"number_one_of": true,
+	"number_any_of": true,
+	"number_all_of": true,
+}
+
+// templateParam matches the parameters of a path template.
+var templateParam = regexp.MustCompile(`{([^}]+)}`)
+
+// validateStructure validates the document against the Swagger 2.0 schema.
+func validateStructure(doc json.RawMessage, raw []byte) error {
+	schema, err := json.Marshal(spec.MustLoadSwagger20Schema())
+	if err != nil {
+		return err
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(doc))
+	if err != nil {
+		return err
+	}
+
+	issues := []SpecIssue{}
+
+	for _, v := range result.Errors() {
+		issues = append(issues, SpecIssue{
+			Pointer: resultPointer(v),
+			Message: v.Description(),
+		})
+	}
+
+	return newValidationError(leafIssues(issues, result.Errors()), raw)
+}
+
+// leafIssues drops the composition issues of the nodes holding other issues.
+func leafIssues(issues []SpecIssue, errs []gojsonschema.ResultError) []SpecIssue {
+	leaves := []SpecIssue{}
+	seen := map[string]bool{}
+
+	for i, issue := range issues {
+		if compositions[errs[i].Type()] && hasDescendant(issues, issue.Pointer) {
+			continue
+		}
+
+		key := issue.Pointer + " " + issue.Message
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		leaves = append(leaves, issue)
+	}
+
+	return leaves
+}
+
+func hasDescendant(issues []SpecIssue, pointer string) bool {
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.Pointer, pointer+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateSemantics validates the rules the Swagger 2.0 schema cannot
+// express, on the expanded document.
+func validateSemantics(s *spec.Swagger, raw []byte) error {
+	issues := []SpecIssue{}
+	operationIDs := map[string]string{}
+
+	paths := []string{}
+	if s.Paths != nil {
+		for path := range s.Paths.Paths {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := s.Paths.Paths[path]
+		itemPointer := "/paths/" + escapePointer(path)
+
+		issues = append(issues, pathParamIssues(path, item.Parameters, itemPointer)...)
+
+		for _, method := range methods {
+			op := operationOf(item, method)
+			if op == nil {
+				continue
+			}
+
+			opPointer := itemPointer + "/" + strings.ToLower(method)
+
+			if op.ID != "" {
+				if previous, ok := operationIDs[op.ID]; ok {
+					issues = append(issues, SpecIssue{
+						Pointer: opPointer + "/operationId",
+						Message: fmt.Sprintf("operationId '%s' is already declared at %s", op.ID, previous),
+					})
+				} else {
+					operationIDs[op.ID] = opPointer + "/operationId"
+				}
+			}
+
+			issues = append(issues, pathParamIssues(path, op.Parameters, opPointer)...)
+			issues = append(issues, undeclaredPathParamIssues(path, item, op, opPointer)...)
+			issues = append(issues, schemaIssues(op, opPointer)...)
+		}
+	}
+
+	names := []string{}
+	for name := range s.Definitions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := s.Definitions[name]
+		issues = append(issues, compileIssues(&schema, "/definitions/"+escapePointer(name))...)
+	}
+
+	return newValidationError(issues, raw)
+}
+
+// pathParamIssues checks the path parameters declared at the pointer, by a
+// path item or an operation, against the path template.
+func pathParamIssues(path string, params []spec.Parameter, pointer string) []SpecIssue {
+	issues := []SpecIssue{}
+	template := map[string]bool{}
+
+	for _, m := range templateParam.FindAllStringSubmatch(path, -1) {
+		template[m[1]] = true
+	}
+
+	for i, param := range params {
+		if param.In == "path" && !template[param.Name] {
+			issues = append(issues, SpecIssue{
+				Pointer: fmt.Sprintf("%s/parameters/%d", pointer, i),
+				Message: fmt.Sprintf("path parameter '%s' is not in the path template", param.Name),
+			})
+		}
+	}
+
+	return issues
+}
+
+// undeclaredPathParamIssues checks the path template parameters are declared
+// by the path item or the operation.
+func undeclaredPathParamIssues(path string, item spec.PathItem, op *spec.Operation, opPointer string) []SpecIssue {
+	issues := []SpecIssue{}
+	declared := map[string]bool{}
+
+	for _, param := range mergeParameters(item.Parameters, op.Parameters) {
+		if param.In == "path" {
+			declared[param.Name] = true
+		}
+	}
+
+	for _, m := range templateParam.FindAllStringSubmatch(path, -1) {
+		if !declared[m[1]] {
+			issues = append(issues, SpecIssue{
+				Pointer: opPointer,
+				Message: fmt.Sprintf("path parameter '%s' is not declared", m[1]),
+			})
+		}
+	}
+
+	return issues
+}
+
+// schemaIssues compiles the body parameter and response schemas.
+func schemaIssues(op *spec.Operation, opPointer string) []SpecIssue {
+	issues := []SpecIssue{}
+
+	for i, param := range op.Parameters {
+		if param.Schema != nil {
+			issues = append(issues, compileIssues(param.Schema, fmt.Sprintf("%s/parameters/%d/schema", opPointer, i))...)
+		}
+	}
+
+	if op.Responses == nil {
+		return issues
+	}
+
+	if res := op.Responses.Default; res != nil && res.Schema != nil {
+		issues = append(issues, compileIssues(res.Schema, opPointer+"/responses/default/schema")...)
+	}
+
+	codes := []int{}
+	for code := range op.Responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	for _, code := range codes {
+		if res := op.Responses.StatusCodeResponses[code]; res.Schema != nil {
+			issues = append(issues, compileIssues(res.Schema, fmt.Sprintf("%s/responses/%d/schema", opPointer, code))...)
+		}
+	}
+
+	return issues
+}
+
+// compileIssues returns an issue when the schema cannot be compiled. File
+// schemas are a Swagger extension unknown to json schema, so are skipped.
+func compileIssues(schema *spec.Schema, pointer string) []SpecIssue {
+	if schema.Type.Contains("file") {
+		return nil
+	}
+
+	if _, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema)); err != nil {
+		return []SpecIssue{{Pointer: pointer, Message: fmt.Sprintf("invalid schema: %s", err)}}
+	}
+
+	return nil
+}
+
+// newValidationError returns the validation error of the issues, referencing
+// their lines in the raw document, or nil when there are none.
+func newValidationError(issues []SpecIssue, raw []byte) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	var root yaml.Node
+
+	if err := yaml.Unmarshal(raw, &root); err == nil {
+		for i := range issues {
+			issues[i].Line = pointerLine(&root, issues[i].Pointer)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	return &ValidationError{issues}
+}
+
+// pointerLine returns the line of the node at the json pointer, or of its
+// closest existing ancestor.
+func pointerLine(root *yaml.Node, pointer string) int {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line := node.Line
+
+	if pointer == "" {
+		return line
+	}
+
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		node = childNode(node, token)
+
+		if node == nil {
+			break
+		}
+
+		line = node.Line
+	}
+
+	return line
+}
+
+// childNode returns the child of a mapping or sequence node.
+func childNode(node *yaml.Node, token string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == token {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
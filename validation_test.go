@@ -0,0 +1,161 @@
+package assert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestLoadValidation(t *testing.T) {
+	type tt struct {
+		doc    string
+		issues []SpecIssue
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("valid document", tt{
+		doc: `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "operationId": "findPet",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`,
+	})
+
+	tests.Add("structural errors", tt{
+		doc: `{
+  "swagger": "2.0",
+  "info": {"title": "pets"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {"200": {}}
+      }
+    }
+  }
+}`,
+		issues: []SpecIssue{
+			{Pointer: "/info/version", Line: 3, Message: "version is required"},
+			{Pointer: "/paths/~1pets/get/responses/200/description", Line: 7, Message: "description is required"},
+		},
+	})
+
+	tests.Add("yaml document", tt{
+		doc: `swagger: "2.0"
+info:
+  title: pets
+paths:
+  /pets:
+    get:
+      responses:
+        200: {}
+`,
+		issues: []SpecIssue{
+			{Pointer: "/info/version", Line: 3, Message: "version is required"},
+			{Pointer: "/paths/~1pets/get/responses/200/description", Line: 8, Message: "description is required"},
+		},
+	})
+
+	tests.Add("semantic errors", tt{
+		doc: `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "operationId": "findPets",
+        "parameters": [{"name": "id", "in": "path", "required": true, "type": "integer"}],
+        "responses": {"200": {"description": "pets"}}
+      }
+    },
+    "/pets/{id}": {
+      "get": {
+        "operationId": "findPets",
+        "responses": {"200": {"description": "pet"}}
+      }
+    }
+  }
+}`,
+		issues: []SpecIssue{
+			{Pointer: "/paths/~1pets/get/parameters/0", Line: 8, Message: "path parameter 'id' is not in the path template"},
+			{Pointer: "/paths/~1pets~1{id}/get", Line: 13, Message: "path parameter 'id' is not declared"},
+			{Pointer: "/paths/~1pets~1{id}/get/operationId", Line: 14, Message: "operationId 'findPets' is already declared at /paths/~1pets/get/operationId"},
+		},
+	})
+
+	tests.Add("path item parameters", tt{
+		doc: `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "parameters": [{"name": "bogus", "in": "path", "required": true, "type": "string"}],
+      "get": {"responses": {"200": {"description": "pets"}}},
+      "put": {"responses": {"200": {"description": "pets"}}}
+    }
+  }
+}`,
+		issues: []SpecIssue{
+			{Pointer: "/paths/~1pets/parameters/0", Line: 6, Message: "path parameter 'bogus' is not in the path template"},
+		},
+	})
+
+	tests.Add("invalid pattern", tt{
+		doc: `{
+  "swagger": "2.0",
+  "info": {"title": "pets", "version": "1.0"},
+  "paths": {},
+  "definitions": {
+    "Pet": {"type": "string", "pattern": "(["}
+  }
+}`,
+		issues: []SpecIssue{
+			{Pointer: "/definitions/Pet/pattern", Line: 6, Message: "Does not match format 'regex'"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		_, err := LoadFromReader(strings.NewReader(tt.doc), WithValidation())
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			if tt.issues != nil || err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			return
+		}
+
+		if d := testy.DiffInterface(tt.issues, verr.Issues); d != nil {
+			t.Error(d)
+		}
+	})
+}
+
+func TestLoadValidationFixture(t *testing.T) {
+	_, err := LoadFromURI("./fixtures/docs.json", WithValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{[]SpecIssue{
+		{Pointer: "/info/version", Line: 3, Message: "version is required"},
+		{Pointer: "/paths", Message: "paths is required"},
+	}}
+
+	expected := "invalid document: line 3 /info/version: version is required; /paths: paths is required"
+
+	testy.Error(t, expected, err)
+}
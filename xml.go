@@ -0,0 +1,242 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// xmlNode is a generic xml element.
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     string
+}
+
+// decodeXML decodes a xml document into a json compatible structure,
+// following the xml hints of the schema.
+func decodeXML(data []byte, schema Body) (interface{}, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s, _ := schema.(*spec.Schema)
+
+	return xmlValue(root, s), nil
+}
+
+func parseXML(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	stack := []*xmlNode{}
+
+	var root *xmlNode
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: t.Name, attrs: t.Attr}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			} else if root == nil {
+				root = node
+			}
+
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, io.EOF
+	}
+
+	return root, nil
+}
+
+// xmlValue converts the node to a value of the schema type.
+func xmlValue(node *xmlNode, schema *spec.Schema) interface{} {
+	switch {
+	case schema == nil:
+		return xmlGeneric(node)
+	case schema.Type.Contains("array"):
+		return xmlItems(node.children, schema)
+	case isObjectSchema(schema):
+		return xmlObject(node, schema)
+	}
+
+	return coerceValue(schema, strings.TrimSpace(node.text))
+}
+
+func xmlObject(node *xmlNode, schema *spec.Schema) map[string]interface{} {
+	object := map[string]interface{}{}
+	used := map[*xmlNode]bool{}
+	usedAttrs := map[string]bool{}
+
+	for _, s := range flattenSchemas(schema) {
+		for name, prop := range s.Properties {
+			prop := prop
+			xmlName := xmlElementName(&prop, name)
+
+			if prop.XML != nil && prop.XML.Attribute {
+				for _, attr := range node.attrs {
+					if xmlNameMatches(attr.Name, xmlName, &prop) {
+						object[name] = coerceValue(&prop, attr.Value)
+						usedAttrs[attr.Name.Local] = true
+					}
+				}
+
+				continue
+			}
+
+			if prop.Type.Contains("array") && (prop.XML == nil || !prop.XML.Wrapped) {
+				itemName := name
+				if prop.Items != nil && prop.Items.Schema != nil {
+					itemName = xmlElementName(prop.Items.Schema, name)
+				}
+
+				children := []*xmlNode{}
+
+				for _, child := range node.children {
+					if xmlNameMatches(child.name, itemName, &prop) {
+						children = append(children, child)
+						used[child] = true
+					}
+				}
+
+				if len(children) > 0 {
+					object[name] = xmlItems(children, &prop)
+				}
+
+				continue
+			}
+
+			for _, child := range node.children {
+				if xmlNameMatches(child.name, xmlName, &prop) {
+					object[name] = xmlValue(child, &prop)
+					used[child] = true
+				}
+			}
+		}
+	}
+
+	for _, attr := range node.attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || usedAttrs[attr.Name.Local] {
+			continue
+		}
+
+		object[attr.Name.Local] = attr.Value
+	}
+
+	for _, child := range node.children {
+		if !used[child] {
+			object[child.name.Local] = xmlGeneric(child)
+		}
+	}
+
+	return object
+}
+
+func xmlItems(children []*xmlNode, schema *spec.Schema) []interface{} {
+	items := []interface{}{}
+
+	for i, child := range children {
+		var item *spec.Schema
+
+		if schema.Items != nil {
+			item = schema.Items.Schema
+
+			if item == nil && i < len(schema.Items.Schemas) {
+				item = &schema.Items.Schemas[i]
+			}
+		}
+
+		items = append(items, xmlValue(child, item))
+	}
+
+	return items
+}
+
+// xmlGeneric converts a node without schema, using maps for elements with
+// children and strings for the others.
+func xmlGeneric(node *xmlNode) interface{} {
+	if len(node.children) == 0 && len(node.attrs) == 0 {
+		return strings.TrimSpace(node.text)
+	}
+
+	object := map[string]interface{}{}
+
+	for _, attr := range node.attrs {
+		object[attr.Name.Local] = attr.Value
+	}
+
+	for _, child := range node.children {
+		value := xmlGeneric(child)
+
+		switch current := object[child.name.Local].(type) {
+		case nil:
+			object[child.name.Local] = value
+		case []interface{}:
+			object[child.name.Local] = append(current, value)
+		default:
+			object[child.name.Local] = []interface{}{current, value}
+		}
+	}
+
+	return object
+}
+
+// xmlElementName returns the xml name of a property.
+func xmlElementName(schema *spec.Schema, name string) string {
+	if schema.XML != nil && schema.XML.Name != "" {
+		return schema.XML.Name
+	}
+
+	return name
+}
+
+func xmlNameMatches(n xml.Name, name string, schema *spec.Schema) bool {
+	if n.Local != name {
+		return false
+	}
+
+	if schema.XML != nil && schema.XML.Namespace != "" {
+		return n.Space == schema.XML.Namespace
+	}
+
+	return true
+}
+
+func isObjectSchema(schema *spec.Schema) bool {
+	if schema.Type.Contains("object") || len(schema.Properties) > 0 {
+		return true
+	}
+
+	for _, s := range flattenSchemas(schema)[1:] {
+		if isObjectSchema(s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package assert
+
+import (
+	"testing"
+
+	"gitlab.com/flimzy/testy"
+)
+
+func TestDecodeXML(t *testing.T) {
+	type tt struct {
+		path     string
+		method   string
+		status   int
+		data     string
+		expected interface{}
+		err      string
+	}
+
+	tests := testy.NewTable()
+
+	tests.Add("invalid xml", tt{
+		path:   "/api/pets",
+		method: "POST",
+		data:   "<pet>",
+		err:    "XML syntax error on line 1: unexpected EOF",
+	})
+
+	tests.Add("empty", tt{
+		path:   "/api/pets",
+		method: "POST",
+		data:   "",
+		err:    "EOF",
+	})
+
+	tests.Add("object", tt{
+		path:   "/api/pets",
+		method: "POST",
+		data: `<pet id="1" xmlns="http://example.com">
+			<full-name>doggo</full-name>
+			<vaccinated>true</vaccinated>
+			<tags><tag>a</tag><tag>b</tag></tags>
+			<photo>1.png</photo>
+			<photo>2.png</photo>
+			<color>black</color>
+		</pet>`,
+		expected: map[string]interface{}{
+			"id":         int64(1),
+			"name":       "doggo",
+			"vaccinated": true,
+			"tags":       []interface{}{"a", "b"},
+			"photos":     []interface{}{"1.png", "2.png"},
+			"color":      "black",
+		},
+	})
+
+	tests.Add("wrapped array", tt{
+		path:   "/api/pets",
+		method: "GET",
+		status: 200,
+		data:   `<pets><pet id="1"><full-name>doggo</full-name></pet><pet id="a"/></pets>`,
+		expected: []interface{}{
+			map[string]interface{}{"id": int64(1), "name": "doggo"},
+			map[string]interface{}{"id": "a"},
+		},
+	})
+
+	tests.Run(t, func(t *testing.T, tt tt) {
+		doc, _ := LoadFromURI("./fixtures/xml.json")
+
+		schema, _ := doc.RequestBody(tt.path, tt.method)
+		if tt.status != 0 {
+			schema, _ = doc.ResponseBody(tt.path, tt.method, tt.status)
+		}
+
+		got, err := decodeXML([]byte(tt.data), schema)
+		testy.Error(t, tt.err, err)
+
+		if d := testy.DiffInterface(tt.expected, got); d != nil {
+			t.Error(d)
+		}
+	})
+}